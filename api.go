@@ -0,0 +1,118 @@
+package main
+
+import (
+	"net/http"
+	"regexp"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ApiPrefix prefix of all REST API endpoints
+const ApiPrefix = "/api/v1"
+
+// RedactedValue replace secret values in configuration returned by API
+const RedactedValue = "*****"
+
+// ApiValues response of values endpoint
+type ApiValues struct {
+	Time   time.Time      `json:"time"`
+	Count  int            `json:"count"`
+	Values []CounterValue `json:"values"`
+}
+
+// ApiErrorResponse error response of API endpoints
+type ApiErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// ValuesFilter filter of values by server, object, instance and counter, wildcards * and ? are allowed
+type ValuesFilter struct {
+	Server   string
+	Object   string
+	Instance string
+	Counter  string
+	patterns [4]*regexp.Regexp // patterns compiled not empty patterns of server, object, instance and counter
+}
+
+// NewValuesFilter create filter from patterns, empty pattern match all values
+func NewValuesFilter(server string, object string, instance string, counter string) *ValuesFilter {
+	f := &ValuesFilter{Server: server, Object: object, Instance: instance, Counter: counter}
+	for i, pattern := range []string{server, object, instance, counter} {
+		if len(pattern) > 0 {
+			f.patterns[i] = globRegexp(pattern)
+		}
+	}
+	return f
+}
+
+// newValuesFilter read filter from request parameters
+func newValuesFilter(r *http.Request) *ValuesFilter {
+	q := r.URL.Query()
+	return NewValuesFilter(q.Get("server"), q.Get("object"), q.Get("instance"), q.Get("counter"))
+}
+
+// match is value matching all not empty patterns of filter
+func (f *ValuesFilter) match(v *CounterValue) bool {
+	for i, value := range []string{v.Server, v.Object, v.Instance, v.Counter} {
+		if f.patterns[i] != nil && !f.patterns[i].MatchString(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// redacted copy of configuration without passwords
+func (c *Config) redacted() Config {
+	r := *c
+	if len(r.ApiPassword) > 0 {
+		r.ApiPassword = RedactedValue
+	}
+	if len(r.Query.Password) > 0 {
+		r.Query.Password = RedactedValue
+	}
+	return r
+}
+
+// apiValuesHandler HTTP handler for /api/v1/values, last collected values filtered by request parameters
+func apiValuesHandler(w http.ResponseWriter, r *http.Request) {
+	log.WithFields(log.Fields{"metricsUri": r.URL.Path, FieldRoutine: "apiValuesHandler"}).Debugf("request %s", r.URL.Path)
+	filter := newValuesFilter(r)
+	response := ApiValues{Time: time.Now(), Values: make([]CounterValue, 0)}
+	for _, v := range counterValues.list() {
+		if filter.match(&v) {
+			response.Values = append(response.Values, v)
+		}
+	}
+	response.Count = len(response.Values)
+	writeJson(w, http.StatusOK, response)
+}
+
+// apiStatusHandler HTTP handler for /api/v1/status, session and API client state
+func apiStatusHandler(w http.ResponseWriter, r *http.Request) {
+	log.WithFields(log.Fields{"metricsUri": r.URL.Path, FieldRoutine: "apiStatusHandler"}).Debugf("request %s", r.URL.Path)
+	writeJson(w, http.StatusOK, monitorStatus.snapshot())
+}
+
+// apiConfigHandler HTTP handler for /api/v1/config, configuration without passwords
+func apiConfigHandler(w http.ResponseWriter, r *http.Request) {
+	log.WithFields(log.Fields{"metricsUri": r.URL.Path, FieldRoutine: "apiConfigHandler"}).Debugf("request %s", r.URL.Path)
+	writeJson(w, http.StatusOK, config.redacted())
+}
+
+// apiOpenApiHandler HTTP handler for /api/v1/openapi.json, generated OpenAPI schema of API
+func apiOpenApiHandler(w http.ResponseWriter, r *http.Request) {
+	log.WithFields(log.Fields{"metricsUri": r.URL.Path, FieldRoutine: "apiOpenApiHandler"}).Debugf("request %s", r.URL.Path)
+	writeJson(w, http.StatusOK, openApiDocument())
+}
+
+// registerApi add REST API endpoints to router
+func registerApi(router *http.ServeMux) {
+	router.HandleFunc(ApiPrefix+"/values", apiValuesHandler)
+	router.HandleFunc(ApiPrefix+"/status", apiStatusHandler)
+	router.HandleFunc(ApiPrefix+"/config", apiConfigHandler)
+	router.HandleFunc(ApiPrefix+"/stream", apiStreamHandler)
+	router.HandleFunc(ApiPrefix+"/history", apiHistoryHandler)
+	router.HandleFunc(ApiPrefix+"/history/export", apiHistoryExportHandler)
+	router.HandleFunc(ApiPrefix+"/openapi.json", apiOpenApiHandler)
+}
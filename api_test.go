@@ -0,0 +1,54 @@
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestValuesFilter(t *testing.T) {
+	value := CounterValue{Server: "cucm1.example.com", Object: "Partition", Instance: "/common", Counter: "% Used"}
+	tests := []struct {
+		server   string
+		object   string
+		instance string
+		counter  string
+		match    bool
+	}{
+		{match: true},
+		{server: "cucm1*", counter: "% Used", match: true},
+		{server: "cucm2*", match: false},
+		// wildcard match any characters include slash
+		{instance: "*common", match: true},
+		{instance: "/*", match: true},
+		{instance: "?common", match: true},
+		{instance: "common", match: false},
+		{object: "Partition", instance: "/common", counter: "%*", match: true},
+		{object: "Process", match: false},
+	}
+	for _, tt := range tests {
+		f := NewValuesFilter(tt.server, tt.object, tt.instance, tt.counter)
+		if got := f.match(&value); got != tt.match {
+			t.Errorf("filter %+v match is %t, want %t", tt, got, tt.match)
+		}
+	}
+
+	// brackets are literal
+	store := CounterValue{Server: "cuc1", Object: "CUC Message Store", Counter: "Message Size Average [kilobytes]"}
+	if !NewValuesFilter("", "", "", "Message Size Average [kilobytes]").match(&store) {
+		t.Errorf("counter name with brackets doesn't match itself")
+	}
+	if NewValuesFilter("", "", "", "Message Size Average [k]*").match(&store) {
+		t.Errorf("brackets in pattern are used as character class")
+	}
+}
+
+func TestNewValuesFilterRequest(t *testing.T) {
+	r := httptest.NewRequest("GET", "/api/v1/values?server=cucm*&counter=Calls*", nil)
+	f := newValuesFilter(r)
+	if f.Server != "cucm*" || f.Object != "" || f.Instance != "" || f.Counter != "Calls*" {
+		t.Errorf("filter from request %+v", f)
+	}
+	if !f.match(&CounterValue{Server: "cucm1", Object: "Cisco CallManager", Counter: "CallsActive"}) {
+		t.Errorf("filter from request doesn't match value")
+	}
+}
@@ -0,0 +1,294 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	BusyHourGroup            = "Cisco CallManager"    // BusyHourGroup group with source counters
+	BusyHourWindow           = 24                     // BusyHourWindow number of hours in rolling window
+	BusyHourMaxSampleGap     = 2 * time.Hour          // BusyHourMaxSampleGap longer gap between samples isn't assigned to any hour
+	BusyHourDayFormat        = "2006-01-02"           // BusyHourDayFormat key format for daily peaks, day is in UTC as hours
+	BusyHourDefaultStateFile = "busy_hour_state.json" // BusyHourDefaultStateFile default file for persisted state
+	BusyHourSaveInterval     = time.Minute            // BusyHourSaveInterval minimal time between writes of changed state
+)
+
+// BusyHour compute busy hour call attempts (BHCA) and peak concurrent calls per node and cluster
+type BusyHour struct {
+	mutex     sync.Mutex
+	fileName  string                   // fileName where state is persisted
+	Nodes     map[string]*busyHourNode `json:"nodes"`   // Nodes state per CUCM node
+	Cluster   busyHourNode             `json:"cluster"` // Cluster state for whole cluster
+	attempted map[string]float64       // attempted actual collected CallsAttempted per node
+	active    map[string]float64       // active actual collected CallsActive per node
+	desc      busyHourDesc             // desc Prometheus descriptions
+	changed   bool                     // changed hourly window or daily peak changed after last save
+	savedAt   time.Time                // savedAt time of last save
+}
+
+// busyHourNode rolling hourly windows for one node or cluster
+type busyHourNode struct {
+	LastAttempted float64            `json:"lastAttempted"` // LastAttempted last cumulative value of CallsAttempted
+	LastTime      time.Time          `json:"lastTime"`      // LastTime time of last sample
+	Hours         map[int64]float64  `json:"hours"`         // Hours call attempts per hour, key is hour start unix time
+	DailyPeak     map[string]float64 `json:"dailyPeak"`     // DailyPeak peak concurrent calls per UTC day
+}
+
+type busyHourDesc struct {
+	attempts        *prometheus.Desc
+	start           *prometheus.Desc
+	current         *prometheus.Desc
+	peak            *prometheus.Desc
+	clusterAttempts *prometheus.Desc
+	clusterStart    *prometheus.Desc
+	clusterCurrent  *prometheus.Desc
+	clusterPeak     *prometheus.Desc
+}
+
+// NewBusyHour create busy hour computation and load persisted state from file
+func NewBusyHour(fileName string) *BusyHour {
+	b := &BusyHour{
+		fileName:  fileName,
+		Nodes:     make(map[string]*busyHourNode),
+		Cluster:   *newBusyHourNode(),
+		attempted: make(map[string]float64),
+		active:    make(map[string]float64),
+		desc: busyHourDesc{
+			attempts:        prometheus.NewDesc("cucm_busy_hour_call_attempts", "Call attempts in busiest hour of last 24 hours", []string{"server"}, nil),
+			start:           prometheus.NewDesc("cucm_busy_hour_start_timestamp_seconds", "Start of busiest hour of last 24 hours", []string{"server"}, nil),
+			current:         prometheus.NewDesc("cucm_current_hour_call_attempts", "Call attempts in current hour", []string{"server"}, nil),
+			peak:            prometheus.NewDesc("cucm_daily_peak_calls_active", "Peak of concurrent active calls in current day", []string{"server"}, nil),
+			clusterAttempts: prometheus.NewDesc("cucm_cluster_busy_hour_call_attempts", "Cluster call attempts in busiest hour of last 24 hours", nil, nil),
+			clusterStart:    prometheus.NewDesc("cucm_cluster_busy_hour_start_timestamp_seconds", "Start of cluster busiest hour of last 24 hours", nil, nil),
+			clusterCurrent:  prometheus.NewDesc("cucm_cluster_current_hour_call_attempts", "Cluster call attempts in current hour", nil, nil),
+			clusterPeak:     prometheus.NewDesc("cucm_cluster_daily_peak_calls_active", "Cluster peak of concurrent active calls in current day", nil, nil),
+		},
+	}
+	if err := b.load(); err != nil {
+		log.WithFields(log.Fields{FieldRoutine: "NewBusyHour", "file": fileName}).Warnf("problem load busy hour state, start with empty. Error: %s", err)
+	}
+	return b
+}
+
+func newBusyHourNode() *busyHourNode {
+	return &busyHourNode{Hours: make(map[int64]float64), DailyPeak: make(map[string]float64)}
+}
+
+// observe store collected value for next update
+func (b *BusyHour) observe(server string, group string, counter string, value float64) {
+	if group != BusyHourGroup {
+		return
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	switch counter {
+	case CallsAttempted:
+		b.attempted[server] = value
+	case CallsActive:
+		b.active[server] = value
+	}
+}
+
+// update process values observed from one collection, changed state is persisted at most once per BusyHourSaveInterval
+func (b *BusyHour) update(now time.Time) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	hour := now.Truncate(time.Hour).Unix()
+	if _, ok := b.Cluster.Hours[hour]; !ok {
+		// new hour in rolling window
+		b.changed = true
+	}
+	day := busyHourDay(now)
+	clusterAttempts := float64(0)
+	for server, value := range b.attempted {
+		node, ok := b.Nodes[server]
+		if !ok {
+			node = newBusyHourNode()
+			b.Nodes[server] = node
+		}
+		delta := value - node.LastAttempted
+		if delta < 0 {
+			// counter reset after service restart
+			delta = value
+		}
+		if !node.LastTime.IsZero() && now.Sub(node.LastTime) < BusyHourMaxSampleGap && delta > 0 {
+			node.Hours[hour] += delta
+			clusterAttempts += delta
+			b.changed = true
+		}
+		node.LastAttempted = value
+		node.LastTime = now
+	}
+	b.Cluster.Hours[hour] += clusterAttempts
+	b.Cluster.LastTime = now
+
+	clusterActive := float64(0)
+	for server, value := range b.active {
+		node, ok := b.Nodes[server]
+		if !ok {
+			node = newBusyHourNode()
+			b.Nodes[server] = node
+		}
+		if value > node.DailyPeak[day] {
+			node.DailyPeak[day] = value
+			b.changed = true
+		}
+		clusterActive += value
+	}
+	if clusterActive > b.Cluster.DailyPeak[day] {
+		b.Cluster.DailyPeak[day] = clusterActive
+		b.changed = true
+	}
+	for _, node := range append([]*busyHourNode{&b.Cluster}, b.nodeList()...) {
+		node.expire(now)
+	}
+	b.attempted = make(map[string]float64)
+	b.active = make(map[string]float64)
+
+	if b.changed && now.Sub(b.savedAt) >= BusyHourSaveInterval {
+		b.persist(now, "BusyHour.update")
+	}
+}
+
+// close persist unsaved changes, called on program end
+func (b *BusyHour) close() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.changed {
+		b.persist(time.Now(), "BusyHour.close")
+	}
+}
+
+// persist save state and log problem, state stay changed when save failed
+func (b *BusyHour) persist(now time.Time, routine string) {
+	if err := b.save(); err != nil {
+		log.WithFields(log.Fields{FieldRoutine: routine, "file": b.fileName}).Errorf("problem save busy hour state. Error: %s", err)
+		return
+	}
+	b.changed = false
+	b.savedAt = now
+}
+
+// expire remove hours and days out of rolling window
+func (n *busyHourNode) expire(now time.Time) {
+	oldest := now.Truncate(time.Hour).Add(-time.Hour * (BusyHourWindow - 1)).Unix()
+	for hour := range n.Hours {
+		if hour < oldest {
+			delete(n.Hours, hour)
+		}
+	}
+	today := busyHourDay(now)
+	for day := range n.DailyPeak {
+		if day != today {
+			delete(n.DailyPeak, day)
+		}
+	}
+}
+
+// busyHourDay key of daily peak, uses UTC same as hour buckets aligned to unix time
+func busyHourDay(now time.Time) string {
+	return now.UTC().Format(BusyHourDayFormat)
+}
+
+// busiest return start and attempts of busiest hour, on same attempts the latest hour win
+func (n *busyHourNode) busiest() (start int64, attempts float64) {
+	hours := make([]int64, 0, len(n.Hours))
+	for hour := range n.Hours {
+		hours = append(hours, hour)
+	}
+	sort.Slice(hours, func(i, j int) bool { return hours[i] < hours[j] })
+	for _, hour := range hours {
+		if n.Hours[hour] >= attempts {
+			start, attempts = hour, n.Hours[hour]
+		}
+	}
+	return start, attempts
+}
+
+// load state from file, missing file isn't error
+func (b *BusyHour) load() error {
+	content, err := os.ReadFile(b.fileName)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(content, b); err != nil {
+		return err
+	}
+	if b.Nodes == nil {
+		b.Nodes = make(map[string]*busyHourNode)
+	}
+	for _, node := range append([]*busyHourNode{&b.Cluster}, b.nodeList()...) {
+		if node.Hours == nil {
+			node.Hours = make(map[int64]float64)
+		}
+		if node.DailyPeak == nil {
+			node.DailyPeak = make(map[string]float64)
+		}
+	}
+	return nil
+}
+
+// save state to file, write temporary file first for prevent broken state
+func (b *BusyHour) save() error {
+	content, err := json.Marshal(b)
+	if err != nil {
+		return err
+	}
+	tmp := b.fileName + ".tmp"
+	if err = os.WriteFile(tmp, content, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, b.fileName)
+}
+
+func (b *BusyHour) nodeList() []*busyHourNode {
+	nodes := make([]*busyHourNode, 0, len(b.Nodes))
+	for _, node := range b.Nodes {
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// Describe implements prometheus.Collector
+func (b *BusyHour) Describe(ch chan<- *prometheus.Desc) {
+	ch <- b.desc.attempts
+	ch <- b.desc.start
+	ch <- b.desc.current
+	ch <- b.desc.peak
+	ch <- b.desc.clusterAttempts
+	ch <- b.desc.clusterStart
+	ch <- b.desc.clusterCurrent
+	ch <- b.desc.clusterPeak
+}
+
+// Collect implements prometheus.Collector
+func (b *BusyHour) Collect(ch chan<- prometheus.Metric) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	now := time.Now()
+	hour := now.Truncate(time.Hour).Unix()
+	day := busyHourDay(now)
+	for server, node := range b.Nodes {
+		start, attempts := node.busiest()
+		ch <- prometheus.MustNewConstMetric(b.desc.attempts, prometheus.GaugeValue, attempts, server)
+		ch <- prometheus.MustNewConstMetric(b.desc.start, prometheus.GaugeValue, float64(start), server)
+		ch <- prometheus.MustNewConstMetric(b.desc.current, prometheus.GaugeValue, node.Hours[hour], server)
+		ch <- prometheus.MustNewConstMetric(b.desc.peak, prometheus.GaugeValue, node.DailyPeak[day], server)
+	}
+	start, attempts := b.Cluster.busiest()
+	ch <- prometheus.MustNewConstMetric(b.desc.clusterAttempts, prometheus.GaugeValue, attempts)
+	ch <- prometheus.MustNewConstMetric(b.desc.clusterStart, prometheus.GaugeValue, float64(start))
+	ch <- prometheus.MustNewConstMetric(b.desc.clusterCurrent, prometheus.GaugeValue, b.Cluster.Hours[hour])
+	ch <- prometheus.MustNewConstMetric(b.desc.clusterPeak, prometheus.GaugeValue, b.Cluster.DailyPeak[day])
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// busyHourModTime modification time of state file, zero when file doesn't exist
+func busyHourModTime(t *testing.T, fileName string) time.Time {
+	t.Helper()
+	info, err := os.Stat(fileName)
+	if os.IsNotExist(err) {
+		return time.Time{}
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return info.ModTime()
+}
+
+func TestBusyHourSave(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "state.json")
+	b := NewBusyHour(fileName)
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	collect := func(attempted float64, active float64) {
+		b.observe("node1", BusyHourGroup, CallsAttempted, attempted)
+		b.observe("node1", BusyHourGroup, CallsActive, active)
+		b.update(now)
+	}
+
+	collect(100, 5)
+	if !b.savedAt.Equal(now) {
+		t.Fatalf("first collection isn't saved")
+	}
+	// changed state in save interval is only marked
+	now = now.Add(2 * time.Second)
+	collect(110, 6)
+	if !b.changed || b.savedAt.Equal(now) {
+		t.Fatalf("state saved in save interval")
+	}
+	now = now.Add(BusyHourSaveInterval)
+	collect(120, 6)
+	if b.changed || !b.savedAt.Equal(now) {
+		t.Fatalf("changed state isn't saved after save interval")
+	}
+	// unchanged state isn't saved
+	now = now.Add(2 * BusyHourSaveInterval)
+	collect(120, 4)
+	if b.changed || b.savedAt.Equal(now) {
+		t.Fatalf("unchanged state saved")
+	}
+
+	now = now.Add(2 * time.Second)
+	collect(125, 4)
+	b.close()
+	if b.changed || busyHourModTime(t, fileName).IsZero() {
+		t.Fatalf("close doesn't save changed state")
+	}
+	loaded := NewBusyHour(fileName)
+	if got := loaded.Nodes["node1"].Hours[now.Truncate(time.Hour).Unix()]; got != 25 {
+		t.Errorf("loaded hour attempts %v, want 25", got)
+	}
+	if got := loaded.Cluster.DailyPeak[busyHourDay(now)]; got != 6 {
+		t.Errorf("loaded cluster peak %v, want 6", got)
+	}
+}
+
+func TestBusyHourBusiest(t *testing.T) {
+	tests := []struct {
+		name         string
+		hours        map[int64]float64
+		wantStart    int64
+		wantAttempts float64
+	}{
+		{"empty window", map[int64]float64{}, 0, 0},
+		{"highest hour", map[int64]float64{3600: 10, 7200: 30, 10800: 20}, 7200, 30},
+		{"same attempts latest hour win", map[int64]float64{3600: 30, 7200: 30, 10800: 5}, 7200, 30},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := &busyHourNode{Hours: tt.hours}
+			if start, attempts := n.busiest(); start != tt.wantStart || attempts != tt.wantAttempts {
+				t.Errorf("busiest() = %d, %v, want %d, %v", start, attempts, tt.wantStart, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestBusyHourExpire(t *testing.T) {
+	now := time.Date(2024, 3, 1, 23, 30, 0, 0, time.UTC)
+	n := newBusyHourNode()
+	current := now.Truncate(time.Hour)
+	n.Hours[current.Unix()] = 1
+	n.Hours[current.Add(-(BusyHourWindow-1)*time.Hour).Unix()] = 2
+	n.Hours[current.Add(-BusyHourWindow*time.Hour).Unix()] = 3
+	n.DailyPeak[busyHourDay(now)] = 4
+	n.DailyPeak[busyHourDay(now.Add(-24*time.Hour))] = 5
+
+	n.expire(now)
+	if len(n.Hours) != 2 || n.Hours[current.Add(-BusyHourWindow*time.Hour).Unix()] != 0 {
+		t.Errorf("hours after expire %v, want only last %d hours", n.Hours, BusyHourWindow)
+	}
+	if len(n.DailyPeak) != 1 || n.DailyPeak[busyHourDay(now)] != 4 {
+		t.Errorf("daily peaks after expire %v, want only current day", n.DailyPeak)
+	}
+	// day is switched at midnight UTC
+	n.expire(now.Add(time.Hour))
+	if len(n.DailyPeak) != 0 {
+		t.Errorf("daily peaks after midnight UTC %v, want empty", n.DailyPeak)
+	}
+}
+
+func TestBusyHourCounterReset(t *testing.T) {
+	b := NewBusyHour(filepath.Join(t.TempDir(), "state.json"))
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	hour := now.Unix()
+	for _, attempted := range []float64{100, 150, 20, 30} {
+		b.observe("node1", BusyHourGroup, CallsAttempted, attempted)
+		b.update(now)
+		now = now.Add(time.Minute)
+	}
+	// 50 before restart, 20 from restarted counter and 10 after restart
+	if got := b.Nodes["node1"].Hours[hour]; got != 80 {
+		t.Errorf("hour attempts %v, want 80", got)
+	}
+}
+
+func TestBusyHourCluster(t *testing.T) {
+	b := NewBusyHour(filepath.Join(t.TempDir(), "state.json"))
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	collect := func(node1 float64, node2 float64, active1 float64, active2 float64) {
+		b.observe("node1", BusyHourGroup, CallsAttempted, node1)
+		b.observe("node2", BusyHourGroup, CallsAttempted, node2)
+		b.observe("node1", BusyHourGroup, CallsActive, active1)
+		b.observe("node2", BusyHourGroup, CallsActive, active2)
+		// other group is ignored
+		b.observe("node1", "Cisco SIP", CallsAttempted, 1000)
+		b.update(now)
+		now = now.Add(time.Minute)
+	}
+	collect(100, 200, 5, 1)
+	collect(110, 230, 3, 4)
+	collect(130, 240, 2, 2)
+
+	if start, attempts := b.Cluster.busiest(); start != now.Truncate(time.Hour).Unix() || attempts != 70 {
+		t.Errorf("cluster busiest %d, %v, want %d, 70", start, attempts, now.Truncate(time.Hour).Unix())
+	}
+	if got := b.Cluster.DailyPeak[busyHourDay(now)]; got != 7 {
+		t.Errorf("cluster daily peak %v, want 7", got)
+	}
+	if got := b.Nodes["node2"].DailyPeak[busyHourDay(now)]; got != 4 {
+		t.Errorf("node2 daily peak %v, want 4", got)
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// StatusUnknown status name for value out of range of known status values
+const StatusUnknown = "unknown"
+
+// ChannelStatusNames names of channel or port status values reported by MGCP device objects
+var ChannelStatusNames = []string{"unknown", "out_of_service", "idle", "busy", "reserved"}
+
+// ReplicationStateNames names of DB replication states reported by Replicate_State counter
+var ReplicationStateNames = []string{"initializing", "setup_started", "good", "bad", "setup_failed"}
+
+// ChannelStatus count channels (or ports) of devices by actual status, for one channel it works as state set
+type ChannelStatus struct {
+	mutex   sync.Mutex
+	desc    *prometheus.Desc
+	names   []string                  // names of status values by value
+	unknown int                       // unknown index of status for value out of range
+	devices map[string]*channelDevice // devices by joined label values
+}
+
+// channelDevice actual status of channels for one device
+type channelDevice struct {
+	labelValues []string
+	channels    map[int]string // channels status name by channel number
+}
+
+// NewChannelStatus create channel status collector, labels are extended with label status
+//   - names without status unknown are extended with it, value out of range is counted as unknown
+func NewChannelStatus(prometheusName string, help string, labels []string, names []string) *ChannelStatus {
+	names, unknown := statusNames(names)
+	return &ChannelStatus{
+		desc:    prometheus.NewDesc(prometheusName, help, append(append([]string{}, labels...), "status"), nil),
+		names:   names,
+		unknown: unknown,
+		devices: make(map[string]*channelDevice),
+	}
+}
+
+// statusNames names of status values extended with status unknown when missing and index of status unknown
+func statusNames(names []string) ([]string, int) {
+	for i, name := range names {
+		if name == StatusUnknown {
+			return names, i
+		}
+	}
+	return append(append([]string{}, names...), StatusUnknown), len(names)
+}
+
+// statusName name of status for value, value out of range is unknown
+func statusName(names []string, unknown int, value float64) string {
+	status := int(value)
+	if status < 0 || status >= len(names) {
+		status = unknown
+	}
+	return names[status]
+}
+
+// observe store status of one channel
+func (c *ChannelStatus) observe(channel int, value float64, labelValues ...string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	key := strings.Join(labelValues, "\x00")
+	d, ok := c.devices[key]
+	if !ok {
+		d = &channelDevice{labelValues: labelValues, channels: make(map[int]string)}
+		c.devices[key] = d
+	}
+	d.channels[channel] = statusName(c.names, c.unknown, value)
+}
+
+// removeInstance forget device removed from session
+func (c *ChannelStatus) removeInstance(labelValues ...string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.devices, strings.Join(labelValues, "\x00"))
+}
+
+// Describe implements prometheus.Collector
+func (c *ChannelStatus) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect implements prometheus.Collector
+func (c *ChannelStatus) Collect(ch chan<- prometheus.Metric) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for _, d := range c.devices {
+		counts := make(map[string]float64, len(c.names))
+		for _, status := range d.channels {
+			counts[status]++
+		}
+		for _, name := range c.names {
+			ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, counts[name], append(append([]string{}, d.labelValues...), name)...)
+		}
+	}
+}
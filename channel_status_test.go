@@ -0,0 +1,51 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// channelStatusValues collected values by status label
+func channelStatusValues(t *testing.T, c *ChannelStatus) map[string]float64 {
+	t.Helper()
+	values := make(map[string]float64)
+	for _, m := range collectMetrics(t, c) {
+		values[labelValue(m, "status")] = metricValue(m)
+	}
+	return values
+}
+
+func TestChannelStatusUnknown(t *testing.T) {
+	replication := NewChannelStatus("cucm_db_replication_state", "help", []string{"server"}, ReplicationStateNames)
+	replication.observe(0, 7, "node1")
+	want := map[string]float64{"initializing": 0, "setup_started": 0, "good": 0, "bad": 0, "setup_failed": 0, "unknown": 1}
+	if got := channelStatusValues(t, replication); !reflect.DeepEqual(got, want) {
+		t.Errorf("replication state %v, want %v", got, want)
+	}
+
+	// status names with unknown aren't extended
+	channels := NewChannelStatus("cucm_channel_status", "help", []string{"device"}, ChannelStatusNames)
+	channels.observe(1, 2, "gw1")
+	channels.observe(2, -1, "gw1")
+	want = map[string]float64{"unknown": 1, "out_of_service": 0, "idle": 1, "busy": 0, "reserved": 0}
+	if got := channelStatusValues(t, channels); !reflect.DeepEqual(got, want) {
+		t.Errorf("channel status %v, want %v", got, want)
+	}
+}
+
+func TestChannelStatusRemoveInstance(t *testing.T) {
+	channels := NewChannelStatus("cucm_mgcp_pri_channel_status", "help", []string{"server", "device"}, ChannelStatusNames)
+	channels.observe(1, 2, "node1", "gw1")
+	channels.observe(1, 3, "node1", "gw2")
+	channels.removeInstance("node1", "gw1")
+	for _, m := range collectMetrics(t, channels) {
+		if device := labelValue(m, "device"); device != "gw2" {
+			t.Errorf("removed device %s is exported", device)
+		}
+	}
+	// second remove of same device (shared collector) is ignored
+	channels.removeInstance("node1", "gw1")
+	if got := len(collectMetrics(t, channels)); got != len(ChannelStatusNames) {
+		t.Errorf("got %d series, want %d for gw2", got, len(ChannelStatusNames))
+	}
+}
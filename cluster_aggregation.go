@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	AggregationSum = "sum" // AggregationSum sum of values from all nodes
+	AggregationAvg = "avg" // AggregationAvg average of values from all nodes
+	AggregationMin = "min" // AggregationMin minimal value from all nodes
+	AggregationMax = "max" // AggregationMax maximal value from all nodes
+)
+
+// AggregationFunctions list of supported aggregation functions
+var AggregationFunctions = []string{AggregationSum, AggregationAvg, AggregationMin, AggregationMax}
+
+// ClusterAggregation compute cluster aggregates and per-node share ratios from latest collected values
+type ClusterAggregation struct {
+	mutex   sync.Mutex
+	rules   []*aggregationRule
+	values  map[string]map[string]float64 // values latest value by counter and server
+	current map[string]map[string]float64 // current values from actual collection by counter and server
+}
+
+// aggregationRule one configured aggregation with Prometheus descriptions
+type aggregationRule struct {
+	counter  string
+	function string
+	cluster  *prometheus.Desc
+	share    *prometheus.Desc // share is nil when share ratio is not required
+}
+
+// NewClusterAggregation create aggregation collector for configured rules
+func NewClusterAggregation(rules []AggregationRule) *ClusterAggregation {
+	a := &ClusterAggregation{
+		rules:   make([]*aggregationRule, 0, len(rules)),
+		values:  make(map[string]map[string]float64),
+		current: make(map[string]map[string]float64),
+	}
+	for _, rule := range rules {
+		counter := supportedCounter(rule.Counter)
+		if counter == nil {
+			continue
+		}
+		help := fmt.Sprintf("Description for %s not exists", rule.Counter)
+		if details, err := monitors.GetCounterDetails(rule.Counter); err == nil {
+			help = details.description
+		}
+		r := &aggregationRule{
+			counter:  rule.Counter,
+			function: rule.Function,
+			cluster:  prometheus.NewDesc(rule.clusterName(counter.prometheusName), fmt.Sprintf("Cluster %s. %s", rule.Function, help), nil, nil),
+		}
+		if rule.ShareRatio {
+			r.share = prometheus.NewDesc(counter.prometheusName+"_share", fmt.Sprintf("Node share of cluster sum. %s", help), []string{"server"}, nil)
+		}
+		a.rules = append(a.rules, r)
+		a.values[rule.Counter] = make(map[string]float64)
+		a.current[rule.Counter] = make(map[string]float64)
+	}
+	return a
+}
+
+// clusterName Prometheus name for cluster aggregate, i.e. cucm_calls_active -> cucm_cluster_calls_active_max
+func (r *AggregationRule) clusterName(prometheusName string) string {
+	name := "cucm_cluster_" + strings.TrimPrefix(prometheusName, "cucm_")
+	if r.Function != AggregationSum {
+		name = fmt.Sprintf("%s_%s", name, r.Function)
+	}
+	return name
+}
+
+// observe store collected value of counter for server until update
+func (a *ClusterAggregation) observe(server string, group string, counter string, value float64) {
+	if !standardGroup(group) {
+		return
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if values, ok := a.current[counter]; ok {
+		values[server] = value
+	}
+}
+
+// update replace latest values by values from one collection, server without valid value in collection is dropped
+func (a *ClusterAggregation) update() {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	for counter, values := range a.current {
+		a.values[counter] = values
+		a.current[counter] = make(map[string]float64)
+	}
+}
+
+// Describe implements prometheus.Collector
+func (a *ClusterAggregation) Describe(ch chan<- *prometheus.Desc) {
+	for _, r := range a.rules {
+		ch <- r.cluster
+		if r.share != nil {
+			ch <- r.share
+		}
+	}
+}
+
+// Collect implements prometheus.Collector
+func (a *ClusterAggregation) Collect(ch chan<- prometheus.Metric) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	for _, r := range a.rules {
+		values := a.values[r.counter]
+		if len(values) == 0 {
+			continue
+		}
+		sum := float64(0)
+		minValue, maxValue := 0.0, 0.0
+		first := true
+		for _, v := range values {
+			sum += v
+			if first || v < minValue {
+				minValue = v
+			}
+			if first || v > maxValue {
+				maxValue = v
+			}
+			first = false
+		}
+		result := sum
+		switch r.function {
+		case AggregationAvg:
+			result = sum / float64(len(values))
+		case AggregationMin:
+			result = minValue
+		case AggregationMax:
+			result = maxValue
+		}
+		ch <- prometheus.MustNewConstMetric(r.cluster, prometheus.GaugeValue, result)
+		if r.share == nil {
+			continue
+		}
+		for server, v := range values {
+			share := float64(0)
+			if sum != 0 {
+				share = v / sum
+			}
+			ch <- prometheus.MustNewConstMetric(r.share, prometheus.GaugeValue, share, server)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import "testing"
+
+// aggregationCollect collect cluster value and share ratios by server for one rule
+func aggregationCollect(t *testing.T, a *ClusterAggregation) (cluster []float64, shares map[string]float64) {
+	t.Helper()
+	shares = make(map[string]float64)
+	for _, m := range collectMetrics(t, a) {
+		if server := labelValue(m, "server"); server != "" {
+			shares[server] = metricValue(m)
+		} else {
+			cluster = append(cluster, metricValue(m))
+		}
+	}
+	return cluster, shares
+}
+
+// aggregationObserve observe one collection of counter values by server
+func aggregationObserve(a *ClusterAggregation, counter string, values map[string]float64) {
+	for server, value := range values {
+		a.observe(server, BusyHourGroup, counter, value)
+	}
+	a.update()
+}
+
+func TestClusterAggregationFunctions(t *testing.T) {
+	values := map[string]float64{"node1": 10, "node2": 30, "node3": 20}
+	tests := []struct {
+		function string
+		want     float64
+	}{
+		{AggregationSum, 60},
+		{AggregationAvg, 20},
+		{AggregationMin, 10},
+		{AggregationMax, 30},
+	}
+	for _, tt := range tests {
+		t.Run(tt.function, func(t *testing.T) {
+			a := NewClusterAggregation([]AggregationRule{{Counter: RegisteredHardwarePhones, Function: tt.function}})
+			aggregationObserve(a, RegisteredHardwarePhones, values)
+			cluster, shares := aggregationCollect(t, a)
+			if len(cluster) != 1 || cluster[0] != tt.want {
+				t.Errorf("cluster %s %v, want %v", tt.function, cluster, tt.want)
+			}
+			if len(shares) != 0 {
+				t.Errorf("share ratio %v exported without shareRatio", shares)
+			}
+		})
+	}
+}
+
+func TestClusterAggregationShare(t *testing.T) {
+	a := NewClusterAggregation([]AggregationRule{{Counter: RegisteredHardwarePhones, Function: AggregationSum, ShareRatio: true}})
+	aggregationObserve(a, RegisteredHardwarePhones, map[string]float64{"node1": 75, "node2": 25})
+	if _, shares := aggregationCollect(t, a); len(shares) != 2 || shares["node1"] != 0.75 || shares["node2"] != 0.25 {
+		t.Errorf("shares %v, want node1 0.75 and node2 0.25", shares)
+	}
+	// zero cluster sum has zero share
+	aggregationObserve(a, RegisteredHardwarePhones, map[string]float64{"node1": 0, "node2": 0})
+	if _, shares := aggregationCollect(t, a); shares["node1"] != 0 || shares["node2"] != 0 {
+		t.Errorf("shares %v for zero sum, want 0", shares)
+	}
+}
+
+func TestClusterAggregationStaleServer(t *testing.T) {
+	a := NewClusterAggregation([]AggregationRule{{Counter: RegisteredHardwarePhones, Function: AggregationSum, ShareRatio: true}})
+	aggregationObserve(a, RegisteredHardwarePhones, map[string]float64{"node1": 10, "node2": 30})
+	// node2 isn't in next collection
+	aggregationObserve(a, RegisteredHardwarePhones, map[string]float64{"node1": 20})
+	cluster, shares := aggregationCollect(t, a)
+	if len(cluster) != 1 || cluster[0] != 20 {
+		t.Errorf("cluster sum %v, want 20 without stale node2", cluster)
+	}
+	if _, ok := shares["node2"]; ok || shares["node1"] != 1 {
+		t.Errorf("shares %v, want only node1 with share 1", shares)
+	}
+	// other counter and not standard group are ignored
+	a.observe("node1", BusyHourGroup, CallsActive, 5)
+	a.observe("node1", "Cisco SIP", RegisteredHardwarePhones, 5)
+	a.update()
+	if cluster, _ := aggregationCollect(t, a); len(cluster) != 0 {
+		t.Errorf("cluster %v after collection without counter, want not exported", cluster)
+	}
+}
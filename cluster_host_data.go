@@ -2,6 +2,7 @@ package main
 
 import (
 	"encoding/xml"
+	"errors"
 	"fmt"
 	log "github.com/sirupsen/logrus"
 	"os"
@@ -19,11 +20,13 @@ const (
 		"</soapenv:Envelope>"
 	EnvelopeList            = "<soap:perfmonListCounter>\r\n<soap:Host>%s</soap:Host>\r\n</soap:perfmonListCounter>"
 	QueryCounterDescription = "<soap:perfmonQueryCounterDescription>\r\n<soap:Counter>%s</soap:Counter>\r\n</soap:perfmonQueryCounterDescription>"
+	EnvelopeListInstance    = "<soap:perfmonListInstance>\r\n<soap:Host>%s</soap:Host>\r\n<soap:Object>%s</soap:Object>\r\n</soap:perfmonListInstance>"
 )
 
 type ClusterHostMonitorData struct {
 	server      string           // server name
 	counterList counterGroupList // counterList list of available counters
+	catalog     counterGroupList // catalog list of all counters on server, not only collected
 }
 
 type counterGroupList struct {
@@ -34,6 +37,7 @@ type counterGroup struct {
 	groupName     string           // name of group same as used in counter group list
 	multiInstance bool             // is multi instance of counter
 	counterName   []CounterDetails // list of counter name
+	instances     []string         // instances of multi instance group registered in session
 }
 type CounterDetails struct {
 	name        string
@@ -42,26 +46,50 @@ type CounterDetails struct {
 
 type XmlListCounterResponse struct {
 	XMLName           xml.Name `xml:"perfmonListCounterResponse"`
-	Text              string   `xml:",chardata"`
-	Ns1               string   `xml:"ns1,attr"`
 	ListCounterReturn []struct {
-		Text           string `xml:",chardata"`
 		Name           string `xml:"Name"`
 		MultiInstance  bool   `xml:"MultiInstance"`
 		ArrayOfCounter struct {
-			Text string `xml:",chardata"`
 			Item []struct {
-				Text string `xml:",chardata"`
 				Name string `xml:"Name"`
 			} `xml:"item"`
 		} `xml:"ArrayOfCounter"`
 	} `xml:"perfmonListCounterReturn"`
 }
 
+// XmlListInstanceResponse response for list instances of object, collect all instance names from response
+type XmlListInstanceResponse struct {
+	XMLName   xml.Name `xml:"perfmonListInstanceResponse"`
+	Instances []string
+}
+
+// UnmarshalXML collect content of all Name elements in response
+func (r *XmlListInstanceResponse) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	r.XMLName = start.Name
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			if t.Name.Local == "Name" {
+				var name string
+				if err = d.DecodeElement(&name, &t); err != nil {
+					return err
+				}
+				r.Instances = append(r.Instances, name)
+			}
+		case xml.EndElement:
+			if t.Name == start.Name {
+				return nil
+			}
+		}
+	}
+}
+
 type XmlDescriptionCounterResponse struct {
 	XMLName                       xml.Name `xml:"perfmonQueryCounterDescriptionResponse"`
-	Text                          string   `xml:",chardata"`
-	Ns1                           string   `xml:"ns1,attr"`
 	QueryCounterDescriptionReturn string   `xml:"perfmonQueryCounterDescriptionReturn"`
 }
 
@@ -69,31 +97,16 @@ func (c *counterGroup) counterPathBase(server string, counter string) string {
 	return fmt.Sprintf("\\\\%s\\%s\\%s", server, c.groupName, counter)
 }
 
-//func (c *counterGroup) counterPathWithInstanceBase(server string, instance string, counter string) string {
-//	return fmt.Sprintf("\\\\%s\\%s(%s)\\%s", server, c.groupName, instance, counter)
-//}
-//
-//func (g *counterGroupList) counterPathBase(server string, counter string) string {
-//	for _, gr := range g.group {
-//		for _, name := range gr.counterName {
-//			if name.name == counter {
-//				return gr.counterPathBase(server, counter)
-//			}
-//		}
-//	}
-//	return ""
-//}
-//
-//func (g *counterGroupList) counterPathWithInstanceBase(server string, instance string, counter string) string {
-//	for _, gr := range g.group {
-//		for _, name := range gr.counterName {
-//			if name.name == counter {
-//				return gr.counterPathWithInstanceBase(server, instance, counter)
-//			}
-//		}
-//	}
-//	return ""
-//}
+func (c *counterGroup) counterPathWithInstanceBase(server string, instance string, counter string) string {
+	return fmt.Sprintf("\\\\%s\\%s(%s)\\%s", server, c.groupName, instance, counter)
+}
+
+// xmlText escape text for use in XML request
+func xmlText(text string) string {
+	b := strings.Builder{}
+	_ = xml.EscapeText(&b, []byte(text))
+	return b.String()
+}
 
 // NewClusterHostMonitorData create new monitored hosts (CUCM servers) with empty counter group
 func NewClusterHostMonitorData(srv string) *ClusterHostMonitorData {
@@ -111,12 +124,18 @@ func (h *ClusterHostMonitorData) createCounterList(data XmlListCounterResponse)
 	log.WithFields(h.logFields("createCounterList")).Tracef("create counter list from response")
 	defer duration(track(log.Fields{FieldRoutine: "createCounterList"}, "procedure ends"))
 	for _, listReturn := range data.ListCounterReturn {
-		if !inSlice(listReturn.Name, AllowedGroupNames) {
+		object := supportedObject(listReturn.Name)
+		if !standardGroup(listReturn.Name) && (object == nil || !object.enabled()) {
 			continue
 		}
 		m := make([]CounterDetails, 0)
 		for _, cnt := range listReturn.ArrayOfCounter.Item {
-			if isNameInAllowedCounter(cnt.Name) && config.Metrics.enablePrometheusCounter(cnt.Name) {
+			if object != nil && object.counterCollected(cnt.Name) {
+				m = append(m, CounterDetails{
+					name:        cnt.Name,
+					description: "",
+				})
+			} else if standardGroup(listReturn.Name) && isNameInAllowedCounter(cnt.Name) && isCounterCollected(cnt.Name) {
 				m = append(m, CounterDetails{
 					name:        cnt.Name,
 					description: "",
@@ -146,30 +165,113 @@ func inSlice(name string, list []string) bool {
 func (h *ClusterHostMonitorData) AddCounters(client *ApiMonitorClient) (err error) {
 	log.WithFields(h.logFields("AddCounter")).Trace("add counters to session")
 	defer duration(track(h.logFields("AddCounter"), "procedure ends"))
-	cnt := ""
-	for _, group := range h.counterList.group {
-		for _, counter := range group.counterName {
-			cnt = fmt.Sprintf("%s<soap:Counter><soap:Name>%s</soap:Name></soap:Counter>", cnt, group.counterPathBase(h.server, counter.name))
+	cnt := strings.Builder{}
+	for g, group := range h.counterList.group {
+		if !group.multiInstance {
+			for _, counter := range group.counterName {
+				cnt.WriteString(fmt.Sprintf("<soap:Counter><soap:Name>%s</soap:Name></soap:Counter>", group.counterPathBase(h.server, counter.name)))
+			}
+			continue
 		}
+		instances, e := h.ListInstances(client, group.groupName)
+		if e != nil {
+			log.WithFields(h.logFields("AddCounter")).Errorf("problem list instances of %s, group is skipped", group.groupName)
+			continue
+		}
+		h.counterList.group[g].instances = instances
+		cnt.WriteString(group.instanceCounters(h.server, instances))
+	}
+	if cnt.Len() == 0 {
+		log.WithFields(h.logFields("AddCounter")).Debug("not any counter for add to session")
+		return nil
 	}
 
-	req := fmt.Sprintf("<soap:perfmonAddCounter><soap:SessionHandle>%s</soap:SessionHandle><soap:ArrayOfCounter>%s</soap:ArrayOfCounter></soap:perfmonAddCounter>", client.session, cnt)
-	body, err := client.processRequest("AddCounters", req)
+	req := fmt.Sprintf("<soap:perfmonAddCounter><soap:SessionHandle>%s</soap:SessionHandle><soap:ArrayOfCounter>%s</soap:ArrayOfCounter></soap:perfmonAddCounter>", client.sessionId(), cnt.String())
+	err = client.processRequest("AddCounters", req, nil)
 
-	if body == "401" {
-		log.WithFields(h.logFields("AddCounter")).Fatal("user not authorize for use performance API")
+	if errors.Is(err, ErrAuthFailure) {
+		log.WithFields(h.logFields("AddCounter")).Fatal(ErrAuthFailure.Error())
 	}
-
-	var fault FaultResponse
-	e := xml.Unmarshal([]byte(body), &fault)
-	if e == nil {
-		log.WithFields(log.Fields{"error": fault.FaultCode, "message": fault.FaultString}).WithFields(h.logFields("AddCounters")).Error("problem add counters")
+	if err != nil {
+		log.WithFields(log.Fields{"message": err}).WithFields(h.logFields("AddCounters")).Error("problem add counters")
 		return err
 	}
 	log.WithFields(h.logFields("AddCounter")).Trace("success add counters to server")
 	return nil
 }
 
+// ListInstances collect instances of multi instance object allowed by object include and exclude rules
+func (h *ClusterHostMonitorData) ListInstances(client *ApiMonitorClient, group string) (instances []string, err error) {
+	log.WithFields(h.logFields("ListInstances")).Tracef("collect instances of %s from server", group)
+	defer duration(track(h.logFields("ListInstances"), "procedure ends"))
+	var list XmlListInstanceResponse
+	err = client.processRequest("ListInstances", fmt.Sprintf(EnvelopeListInstance, h.server, group), &list)
+	if err != nil {
+		return nil, err
+	}
+	instances = make([]string, 0, len(list.Instances))
+	object := supportedObject(group)
+	for _, instance := range list.Instances {
+		if object == nil || object.instanceAllowed(instance) {
+			instances = append(instances, instance)
+		}
+	}
+	log.WithFields(h.logFields("ListInstances")).Debugf("for %s use %d from %d instances", group, len(instances), len(list.Instances))
+	return instances, nil
+}
+
+// instanceCounters SOAP counter elements for all counters of group in instances
+func (c *counterGroup) instanceCounters(server string, instances []string) string {
+	cnt := strings.Builder{}
+	for _, instance := range instances {
+		for _, counter := range c.counterName {
+			cnt.WriteString(fmt.Sprintf("<soap:Counter><soap:Name>%s</soap:Name></soap:Counter>", xmlText(c.counterPathWithInstanceBase(server, instance, counter.name))))
+		}
+	}
+	return cnt.String()
+}
+
+// RefreshInstances list actual instances of multi instance group and add new or remove deleted instances in session
+//   - list of instances in group is updated only for successful requests, failed change is repeated in next refresh
+func (h *ClusterHostMonitorData) RefreshInstances(client *ApiMonitorClient, g int) (added []string, removed []string, err error) {
+	group := &h.counterList.group[g]
+	log.WithFields(h.logFields("RefreshInstances")).Tracef("refresh instances of %s", group.groupName)
+	instances, err := h.ListInstances(client, group.groupName)
+	if err != nil {
+		return nil, nil, err
+	}
+	added = missingInstances(instances, group.instances)
+	removed = missingInstances(group.instances, instances)
+	if len(added) > 0 {
+		req := fmt.Sprintf("<soap:perfmonAddCounter><soap:SessionHandle>%s</soap:SessionHandle><soap:ArrayOfCounter>%s</soap:ArrayOfCounter></soap:perfmonAddCounter>",
+			client.sessionId(), group.instanceCounters(h.server, added))
+		if err = client.processRequest("RefreshInstances", req, nil); err != nil {
+			return nil, nil, err
+		}
+		group.instances = append(group.instances, added...)
+	}
+	if len(removed) > 0 {
+		req := fmt.Sprintf("<soap:perfmonRemoveCounter><soap:SessionHandle>%s</soap:SessionHandle><soap:ArrayOfCounter>%s</soap:ArrayOfCounter></soap:perfmonRemoveCounter>",
+			client.sessionId(), group.instanceCounters(h.server, removed))
+		if err = client.processRequest("RefreshInstances", req, nil); err != nil {
+			return added, nil, err
+		}
+		group.instances = missingInstances(group.instances, removed)
+	}
+	return added, removed, nil
+}
+
+// missingInstances instances from list which aren't in other list
+func missingInstances(list []string, other []string) []string {
+	missing := make([]string, 0)
+	for _, instance := range list {
+		if !inSlice(instance, other) {
+			missing = append(missing, instance)
+		}
+	}
+	return missing
+}
+
 // ListCounters collect all counters from API server for specific CUCM host
 func (h *ClusterHostMonitorData) ListCounters(client *ApiMonitorClient) (err error) {
 	log.WithFields(h.logFields("ListCounters")).Trace("collect counters from server")
@@ -179,26 +281,39 @@ func (h *ClusterHostMonitorData) ListCounters(client *ApiMonitorClient) (err err
 		return nil
 	}
 	s := fmt.Sprintf(EnvelopeList, h.server)
-	body, err := client.processRequest("ListCounters", s)
-	if err != nil && body == "401" {
-		log.WithFields(h.logFields("ListCounters")).Fatal("user not authorize for use performance API")
-	}
-
-	if err != nil {
-		return err
+	var list XmlListCounterResponse
+	err = client.processRequest("ListCounters", s, &list)
+	if errors.Is(err, ErrAuthFailure) {
+		log.WithFields(h.logFields("ListCounters")).Fatal(ErrAuthFailure.Error())
 	}
 
-	var list XmlListCounterResponse
-	err = xml.Unmarshal([]byte(body), &list)
 	if err != nil {
-		log.WithFields(h.logFields("ListCounters")).Errorf("problem convert XML body to struct. Error: %s", err)
 		return err
 	}
 	h.createCounterList(list)
+	h.createCatalog(list)
 	return nil
 }
 
-func (h *ClusterHostMonitorData) ReadCounterDescription(client *ApiMonitorClient) (err error) {
+// createCatalog store all groups and counters provided by server
+func (h *ClusterHostMonitorData) createCatalog(data XmlListCounterResponse) {
+	h.catalog.group = make([]counterGroup, 0, len(data.ListCounterReturn))
+	for _, listReturn := range data.ListCounterReturn {
+		m := make([]CounterDetails, 0, len(listReturn.ArrayOfCounter.Item))
+		for _, cnt := range listReturn.ArrayOfCounter.Item {
+			m = append(m, CounterDetails{name: cnt.Name})
+		}
+		h.catalog.group = append(h.catalog.group, counterGroup{
+			groupName:     listReturn.Name,
+			multiInstance: listReturn.MultiInstance,
+			counterName:   m,
+		})
+	}
+}
+
+// ReadCounterDescription collect descriptions of counters, descriptions are shared between servers in descriptions map
+//   - multi instance counters use path with first instance of group, problem with them isn't reported as error
+func (h *ClusterHostMonitorData) ReadCounterDescription(client *ApiMonitorClient, descriptions map[string]string) (err error) {
 	log.WithFields(h.logFields("ReadCounterDescription")).Trace("collect counters descriptions from server")
 	defer duration(track(h.logFields("ReadCounterDescription"), "procedure ends"))
 	var s string
@@ -209,13 +324,34 @@ func (h *ClusterHostMonitorData) ReadCounterDescription(client *ApiMonitorClient
 	signal.Notify(quit, os.Interrupt)
 
 	for g, group := range h.counterList.group {
+		instance := ""
 		if group.multiInstance {
-			log.WithFields(h.logFields("ReadCounterDescription")).Info("multi-instance not implement yet")
-			continue
+			instances, e := h.ListInstances(client, group.groupName)
+			if e != nil || len(instances) == 0 {
+				log.WithFields(h.logFields("ReadCounterDescription")).Debugf("not any instance of %s for read descriptions", group.groupName)
+				continue
+			}
+			instance = instances[0]
 		}
 
+		object := supportedObject(group.groupName)
 		for c, counter := range group.counterName {
+			if object != nil && object.counter(counter.name) != nil && object.counter(counter.name).channel > 0 {
+				// channel status counters are exported in one metric with own description
+				continue
+			}
+			if object != nil && !object.counterEnabled(counter.name) {
+				// counter required only for computed metrics isn't exported and doesn't need description
+				continue
+			}
+			if d, ok := descriptions[objectKey(group.groupName, counter.name)]; ok {
+				h.counterList.group[g].counterName[c].description = d
+				continue
+			}
 			base = group.counterPathBase(h.server, counter.name)
+			if group.multiInstance {
+				base = xmlText(group.counterPathWithInstanceBase(h.server, instance, counter.name))
+			}
 			select {
 			case <-time.After(time.Millisecond * 2):
 				break
@@ -226,29 +362,23 @@ func (h *ClusterHostMonitorData) ReadCounterDescription(client *ApiMonitorClient
 			log.WithFields(h.logFields("ReadCounterDescription")).WithField(FieldMetricsName, base).
 				Tracef("collect counters descriptions for %s", counter.name)
 			s = fmt.Sprintf(QueryCounterDescription, base)
-			body, errRequest := client.processRequest("ReadCounterDescription", s)
-			if body == "401" {
+			var description XmlDescriptionCounterResponse
+			errRequest := client.processRequest("ReadCounterDescription", s, &description)
+			if errors.Is(errRequest, ErrAuthFailure) || errors.Is(errRequest, ErrRateLimit) {
 				log.WithFields(h.logFields("ReadCounterDescription")).WithField(FieldMetricsName, base).
-					Fatal("user not authorize for use performance API")
+					Fatal(errRequest)
 			}
-			if strings.Contains(body, "RateControl") {
+			if errRequest != nil && group.multiInstance {
 				log.WithFields(h.logFields("ReadCounterDescription")).WithField(FieldMetricsName, base).
-					Fatal("exceeded allowed rate for Perfmon information")
-			}
-			if errRequest != nil {
-				errCounter++
+					Warnf("problem read description of multi instance counter. Error: %s", errRequest)
 				continue
 			}
-
-			var description XmlDescriptionCounterResponse
-			err = xml.Unmarshal([]byte(body), &description)
-			if err != nil {
-				log.WithFields(h.logFields("ReadCounterDescription")).WithField(FieldMetricsName, base).
-					Errorf("problem convert XML body to struct. Error: %s", err)
+			if errRequest != nil {
 				errCounter++
 				continue
 			}
 			h.counterList.group[g].counterName[c].description = description.QueryCounterDescriptionReturn
+			descriptions[objectKey(group.groupName, counter.name)] = description.QueryCounterDescriptionReturn
 		}
 	}
 	if errCounter > 0 {
@@ -264,7 +394,7 @@ func (h *ClusterHostMonitorData) print() string {
 }
 
 func (h *ClusterHostMonitorData) string() string {
-	return fmt.Sprintf("Errors in %s : %d", applicationName, monitors.client.responseErrors)
+	return fmt.Sprintf("Errors in %s : %d", applicationName, monitors.client.responseErrors.Load())
 }
 
 func (h *ClusterHostMonitorData) logFields(operation ...string) log.Fields {
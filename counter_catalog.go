@@ -0,0 +1,158 @@
+package main
+
+import (
+	"html/template"
+	"net/http"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// CatalogCounter one counter from catalog of all counters provided by cluster nodes
+type CatalogCounter struct {
+	Object         string         `json:"object"`
+	Counter        string         `json:"counter"`
+	MultiInstance  bool           `json:"multiInstance"`
+	Description    string         `json:"description,omitempty"`
+	Nodes          []string       `json:"nodes"`
+	Enabled        bool           `json:"enabled"`
+	PrometheusName string         `json:"prometheusName,omitempty"`
+	Values         []CounterValue `json:"values,omitempty"`
+}
+
+// catalogPage data for HTML catalog page
+type catalogPage struct {
+	Title    string
+	Search   string
+	Servers  []string
+	Counters []CatalogCounter
+}
+
+// counterExport is counter exported and with which Prometheus name, standard counters have priority
+func counterExport(object string, counter string) (enabled bool, prometheusName string) {
+	if standardGroup(object) {
+		if c := supportedCounter(counter); c != nil && config.Metrics.enablePrometheusCounter(counter) {
+			return true, c.prometheusName
+		}
+	}
+	if o := supportedObject(object); o != nil {
+		if c := o.counter(counter); c != nil {
+			return o.counterEnabled(counter), o.metricName(c)
+		}
+	}
+	if inSlice(object, AllowedGroupNames) {
+		if c := supportedCounter(counter); c != nil {
+			return false, c.prometheusName
+		}
+	}
+	return false, ""
+}
+
+// HasNode is counter available on node, used by HTML template
+func (c CatalogCounter) HasNode(server string) bool {
+	return inSlice(server, c.Nodes)
+}
+
+// Catalog list all counters from all nodes, search filter counters by object, counter or Prometheus name
+//   - descriptions are known only for collected counters and counters read by query endpoint
+func (s *PerfMonService) Catalog(search string) []CatalogCounter {
+	search = strings.ToLower(strings.TrimSpace(search))
+	values := counterValues.byCounter()
+	index := make(map[string]*CatalogCounter)
+	for _, m := range s.monitors {
+		for _, group := range m.catalog.group {
+			for _, counter := range group.counterName {
+				key := objectKey(group.groupName, counter.name)
+				item, ok := index[key]
+				if !ok {
+					item = &CatalogCounter{Object: group.groupName, Counter: counter.name, MultiInstance: group.multiInstance, Nodes: make([]string, 0)}
+					item.Enabled, item.PrometheusName = counterExport(group.groupName, counter.name)
+					if !item.matchSearch(search) {
+						continue
+					}
+					item.Description = s.catalogDescription(group.groupName, counter.name)
+					item.Values = values[key]
+					index[key] = item
+				}
+				item.Nodes = append(item.Nodes, m.server)
+			}
+		}
+	}
+	catalog := make([]CatalogCounter, 0, len(index))
+	for _, item := range index {
+		catalog = append(catalog, *item)
+	}
+	sort.Slice(catalog, func(i, j int) bool {
+		if catalog[i].Object != catalog[j].Object {
+			return catalog[i].Object < catalog[j].Object
+		}
+		return catalog[i].Counter < catalog[j].Counter
+	})
+	return catalog
+}
+
+// matchSearch is counter matching lower case search text
+func (c *CatalogCounter) matchSearch(search string) bool {
+	if len(search) == 0 {
+		return true
+	}
+	return strings.Contains(strings.ToLower(objectKey(c.Object, c.Counter)), search) ||
+		strings.Contains(strings.ToLower(c.PrometheusName), search)
+}
+
+// catalogDescription description from collected counters or query cache, empty when unknown
+func (s *PerfMonService) catalogDescription(object string, counter string) string {
+	description, _ := knownDescription(object, counter)
+	return description
+}
+
+// wantJson is JSON response requested by format parameter or Accept header
+func wantJson(r *http.Request) bool {
+	return r.URL.Query().Get("format") == "json" || strings.Contains(r.Header.Get("Accept"), "application/json")
+}
+
+// countersHandler HTTP handler for /counters, JSON or searchable HTML page
+func countersHandler(w http.ResponseWriter, r *http.Request) {
+	log.WithFields(log.Fields{"metricsUri": "/counters", FieldRoutine: "countersHandler"}).Debug("request /counters")
+	search := r.URL.Query().Get("q")
+	catalog := monitors.Catalog(search)
+	if wantJson(r) {
+		writeJson(w, http.StatusOK, catalog)
+		return
+	}
+	page := catalogPage{Title: applicationName, Search: search, Servers: config.MonitorNames, Counters: catalog}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := catalogTemplate.Execute(w, page); err != nil {
+		log.WithFields(log.Fields{FieldRoutine: "countersHandler"}).Errorf("problem render counters page. Error: %s", err)
+	}
+}
+
+var catalogTemplate = template.Must(template.New("counters").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>{{.Title}} - counters</title>
+<style>
+body{font-family:sans-serif;font-size:13px}table{border-collapse:collapse}td,th{border:1px solid #ccc;padding:2px 6px;vertical-align:top}
+th{background:#eee;position:sticky;top:0}.on{color:#080;font-weight:bold}.off{color:#999}
+</style></head><body>
+<h2>Counters</h2>
+<form method="get"><input id="search" name="q" value="{{.Search}}" size="50" placeholder="object, counter or Prometheus name" autofocus>
+<input type="submit" value="Search"> <a href="?format=json&amp;q={{.Search}}">JSON</a> <a href="/">Home</a></form>
+<p>{{len .Counters}} counters</p>
+<table id="counters"><thead><tr><th>Object</th><th>Counter</th>{{range .Servers}}<th>{{.}}</th>{{end}}<th>Enabled</th><th>Prometheus name</th><th>Current value</th></tr></thead>
+<tbody>{{range $c := .Counters}}<tr>
+<td>{{$c.Object}}{{if $c.MultiInstance}} (*){{end}}</td><td title="{{$c.Description}}">{{$c.Counter}}</td>
+{{range $.Servers}}<td>{{if $c.HasNode .}}<span class="on">&#10003;</span>{{else}}<span class="off">-</span>{{end}}</td>{{end}}
+<td>{{if $c.Enabled}}<span class="on">yes</span>{{else}}<span class="off">no</span>{{end}}</td>
+<td>{{$c.PrometheusName}}</td>
+<td>{{range $c.Values}}{{.Server}}{{if .Instance}} [{{.Instance}}]{{end}}: {{.Value}}{{if not .Valid}} (status {{.CStatus}}){{end}}<br>{{end}}</td>
+</tr>{{end}}</tbody></table>
+<script>
+document.getElementById("search").addEventListener("input", function () {
+  var text = this.value.toLowerCase();
+  document.querySelectorAll("#counters tbody tr").forEach(function (row) {
+    row.style.display = row.textContent.toLowerCase().indexOf(text) >= 0 ? "" : "none";
+  });
+});
+</script>
+</body></html>
+`))
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// catalogService service with counter catalogs of two nodes
+func catalogService() PerfMonService {
+	return PerfMonService{monitors: []ClusterHostMonitorData{
+		{server: "node1", catalog: counterGroupList{group: []counterGroup{
+			{groupName: BusyHourGroup, counterName: []CounterDetails{{name: CallsActive}, {name: RegisteredHardwarePhones}}},
+			{groupName: "Cisco Unknown", multiInstance: true, counterName: []CounterDetails{{name: "Unknown"}}},
+		}}},
+		{server: "node2", catalog: counterGroupList{group: []counterGroup{
+			{groupName: BusyHourGroup, counterName: []CounterDetails{{name: CallsActive}}},
+		}}},
+	}}
+}
+
+func TestCatalog(t *testing.T) {
+	saved := config.Metrics.CallsActive
+	defer func() { config.Metrics.CallsActive = saved; counterValues.reset() }()
+	counterValues.reset()
+	config.Metrics.CallsActive = true
+	storeDescriptions(map[string]string{objectKey(BusyHourGroup, CallsActive): "active calls"})
+	counterValues.observe("node1", BusyHourGroup, CallsActive, &OneCollectData{Value: 3}, time.Now())
+
+	s := catalogService()
+	catalog := s.Catalog("")
+	if len(catalog) != 3 {
+		t.Fatalf("catalog has %d counters, want 3", len(catalog))
+	}
+	first := catalog[0]
+	if first.Counter != CallsActive || strings.Join(first.Nodes, ",") != "node1,node2" {
+		t.Errorf("first counter %s on nodes %v, want %s on node1,node2", first.Counter, first.Nodes, CallsActive)
+	}
+	if !first.Enabled || first.PrometheusName != "cucm_calls_active" || first.Description != "active calls" {
+		t.Errorf("counter %s enabled %t, name %s, description %s", first.Counter, first.Enabled, first.PrometheusName, first.Description)
+	}
+	if len(first.Values) != 1 || first.Values[0].Value != 3 {
+		t.Errorf("counter %s values %v, want value 3 from node1", first.Counter, first.Values)
+	}
+	if last := catalog[2]; last.Object != "Cisco Unknown" || !last.MultiInstance || last.Enabled || last.PrometheusName != "" {
+		t.Errorf("unsupported counter %+v, want not exported multi instance counter", last)
+	}
+
+	// search by Prometheus name and case insensitive object and counter
+	for _, search := range []string{"hardware_phones", "  REGISTEREDhardware "} {
+		if got := s.Catalog(search); len(got) != 1 || got[0].Counter != RegisteredHardwarePhones {
+			t.Errorf("search %q found %v, want only %s", search, got, RegisteredHardwarePhones)
+		}
+	}
+}
+
+func TestCountersHandler(t *testing.T) {
+	saved := monitors
+	defer func() { monitors = saved }()
+	monitors = catalogService()
+
+	w := httptest.NewRecorder()
+	countersHandler(w, httptest.NewRequest(http.MethodGet, "/counters?format=json&q=unknown", nil))
+	var catalog []CatalogCounter
+	if err := json.NewDecoder(w.Body).Decode(&catalog); err != nil {
+		t.Fatalf("decode JSON catalog error %v", err)
+	}
+	if len(catalog) != 1 || catalog[0].Counter != "Unknown" {
+		t.Errorf("JSON catalog %v, want only counter Unknown", catalog)
+	}
+
+	w = httptest.NewRecorder()
+	countersHandler(w, httptest.NewRequest(http.MethodGet, "/counters?q=calls", nil))
+	if body := w.Body.String(); !strings.Contains(body, "cucm_calls_active") || strings.Contains(body, "Cisco Unknown") {
+		t.Errorf("HTML catalog doesn't contain only searched counters")
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// CounterValue last collected value of one counter
+type CounterValue struct {
+	Server   string    `json:"server"`
+	Object   string    `json:"object"`
+	Instance string    `json:"instance,omitempty"`
+	Counter  string    `json:"counter"`
+	Value    float64   `json:"value"`
+	CStatus  string    `json:"cStatus"`
+	Valid    bool      `json:"valid"`
+	Time     time.Time `json:"time"`
+}
+
+// CounterValues last collected values of all counters in session
+type CounterValues struct {
+	mutex  sync.RWMutex
+	values map[string]*CounterValue // values by counter path server\group\counter
+}
+
+// counterValues last collected values, used by web pages and API
+var counterValues = NewCounterValues()
+
+// NewCounterValues create empty store of collected values
+func NewCounterValues() *CounterValues {
+	return &CounterValues{values: make(map[string]*CounterValue)}
+}
+
+// observe store collected value, invalid sample keep previous value and update only status
+//   - return stored value and if value or status is changed
+func (c *CounterValues) observe(server string, group string, counter string, data *OneCollectData, now time.Time) (CounterValue, bool) {
+	object, instance := splitInstance(group)
+	key := objectKey(server, objectKey(group, counter))
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	v, ok := c.values[key]
+	if !ok {
+		v = &CounterValue{Server: server, Object: object, Instance: instance, Counter: counter}
+		c.values[key] = v
+	}
+	changed := !ok || v.CStatus != data.CStatus
+	v.CStatus = data.CStatus
+	v.Valid = data.valid()
+	if v.Valid {
+		changed = changed || v.Value != data.Value
+		v.Value = data.Value
+		v.Time = now
+	}
+	return *v, changed
+}
+
+// reset remove all values, used when session is closed
+func (c *CounterValues) reset() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.values = make(map[string]*CounterValue)
+}
+
+// list copy of all values sorted by server, object, instance and counter
+func (c *CounterValues) list() []CounterValue {
+	c.mutex.RLock()
+	values := make([]CounterValue, 0, len(c.values))
+	for _, v := range c.values {
+		values = append(values, *v)
+	}
+	c.mutex.RUnlock()
+	sort.Slice(values, func(i, j int) bool {
+		a, b := values[i], values[j]
+		if a.Server != b.Server {
+			return a.Server < b.Server
+		}
+		if a.Object != b.Object {
+			return a.Object < b.Object
+		}
+		if a.Instance != b.Instance {
+			return a.Instance < b.Instance
+		}
+		return a.Counter < b.Counter
+	})
+	return values
+}
+
+// byCounter values grouped by object key (object and counter) from all servers and instances
+func (c *CounterValues) byCounter() map[string][]CounterValue {
+	values := make(map[string][]CounterValue)
+	for _, v := range c.list() {
+		key := objectKey(v.Object, v.Counter)
+		values[key] = append(values[key], v)
+	}
+	return values
+}
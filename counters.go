@@ -1,11 +1,29 @@
 package main
 
+import (
+	"fmt"
+	"strings"
+)
+
 type Counters struct {
 	allowedCounterName string
 	prometheusName     string
 	defaultEnabled     bool
+	cumulative         bool     // cumulative counter is exported as Prometheus counter
+	channel            int      // channel number of channel status counter, statuses of all channels are counted in one metric
+	rate               bool     // rate (percentage) counter is computed from two samples, first sample in session isn't valid
+	states             []string // states names of enum counter, exported as one series per state with value 1 for actual state
+	scale              float64  // scale multiplier for conversion of value to base unit (i.e. kilobytes to bytes), 0 is without conversion
+	required           bool     // required counter is collected for computed metrics of enabled object even when isn't exported
 }
 
+const (
+	ScaleKilobytes = 1024        // ScaleKilobytes convert kilobytes to bytes
+	ScaleMegabytes = 1024 * 1024 // ScaleMegabytes convert megabytes to bytes
+	ScaleKilobits  = 1000        // ScaleKilobits convert kilobits to bits
+	ScaleMillis    = 0.001       // ScaleMillis convert milliseconds to seconds
+)
+
 const (
 	AnnunciatorOutOfResources    = "AnnunciatorOutOfResources"
 	AnnunciatorResourceActive    = "AnnunciatorResourceActive"
@@ -40,10 +58,18 @@ const (
 	ExternalCallControlEnabledCallsCompleted          = "ExternalCallControlEnabledCallsCompleted"
 	ExternalCallControlEnabledFailureTreatmentApplied = "ExternalCallControlEnabledFailureTreatmentApplied"
 
-	FXOPortsActive    = "FXOPortsActive"
-	FXOPortsInService = "FXOPortsInService"
-	FXSPortsActive    = "FXSPortsActive"
-	FXSPortsInService = "FXSPortsInService"
+	FXOPortsActive       = "FXOPortsActive"
+	FXOPortsInService    = "FXOPortsInService"
+	FXSPortsActive       = "FXSPortsActive"
+	FXSPortsInService    = "FXSPortsInService"
+	DatalinkInService    = "DatalinkInService"
+	OutboundBusyAttempts = "OutboundBusyAttempts"
+	PortStatus           = "PortStatus"
+
+	PRIChannelsActive = "PRIChannelsActive"
+	PRISpansInService = "PRISpansInService"
+	T1ChannelsActive  = "T1ChannelsActive"
+	T1SpansInService  = "T1SpansInService"
 
 	HWConferenceActive            = "HWConferenceActive"
 	HWConferenceCompleted         = "HWConferenceCompleted"
@@ -135,6 +161,196 @@ const (
 	RegisteredAnalogAccess        = "RegisteredAnalogAccess"
 	RegisteredMGCPGateway         = "RegisteredMGCPGateway"
 	RegisteredOtherStationDevices = "RegisteredOtherStationDevices"
+
+	// BandwidthAvailable location bandwidth (Cisco Locations LBM)
+	BandwidthAvailable               = "BandwidthAvailable"
+	BandwidthMaximum                 = "BandwidthMaximum"
+	VideoBandwidthAvailable          = "VideoBandwidthAvailable"
+	VideoBandwidthMaximum            = "VideoBandwidthMaximum"
+	ImmersiveVideoBandwidthAvailable = "ImmersiveVideoBandwidthAvailable"
+	ImmersiveVideoBandwidthMaximum   = "ImmersiveVideoBandwidthMaximum"
+
+	// TftpBuildCount TFTP configuration files build counters (Cisco TFTP)
+	TftpBuildCount         = "BuildCount"
+	TftpBuildDeviceCount   = "BuildDeviceCount"
+	TftpBuildUnitCount     = "BuildUnitCount"
+	TftpBuildDialruleCount = "BuildDialruleCount"
+	TftpBuildSoftKeyCount  = "BuildSoftKeyCount"
+	TftpBuildSignCount     = "BuildSignCount"
+
+	// CPUTime processor
+	CPUTime           = "% CPU Time"
+	UserPercentage    = "User Percentage"
+	SystemPercentage  = "System Percentage"
+	IOwaitPercentage  = "IOwait Percentage"
+	IdlePercentage    = "Idle Percentage"
+	NicePercentage    = "Nice Percentage"
+	IrqPercentage     = "Irq Percentage"
+	SoftirqPercentage = "Softirq Percentage"
+
+	// MemUsed memory
+	MemUsed         = "% Mem Used"
+	VMUsed          = "% VM Used"
+	PageUsage       = "% Page Usage"
+	TotalKBytes     = "Total KBytes"
+	UsedKBytes      = "Used KBytes"
+	FreeKBytes      = "Free KBytes"
+	BuffersKBytes   = "Buffers KBytes"
+	CachedKBytes    = "Cached KBytes"
+	TotalSwapKBytes = "Total Swap KBytes"
+	UsedSwapKBytes  = "Used Swap KBytes"
+	FreeSwapKBytes  = "Free Swap KBytes"
+
+	// PartitionUsed disk partition
+	PartitionUsed    = "% Used"
+	TotalMbytes      = "Total Mbytes"
+	UsedMbytes       = "Used Mbytes"
+	QueueLength      = "Queue Length"
+	ReadBytesPerSec  = "Read Bytes Per Sec"
+	WriteBytesPerSec = "Write Bytes Per Sec"
+
+	// BandwidthOversubscription location bandwidth (Cisco Locations LBM)
+	BandwidthOversubscription = "BandwidthOversubscription"
+	OutOfResources            = "OutOfResources"
+	ImmersiveOutOfResources   = "ImmersiveOutOfResources"
+
+	// RSVPMandatoryConnectionsInProgress location RSVP
+	RSVPMandatoryConnectionsInProgress = "RSVP MandatoryConnectionsInProgress"
+	RSVPOptionalConnectionsInProgress  = "RSVP OptionalConnectionsInProgress"
+	RSVPTotalCallsFailed               = "RSVP TotalCallsFailed"
+	RSVPVideoCallsFailed               = "RSVP VideoCallsFailed"
+	RSVPAudioReservationErrorCounts    = "RSVP AudioReservationErrorCounts"
+	RSVPVideoReservationErrorCounts    = "RSVP VideoReservationErrorCounts"
+
+	// CTIConnectionActive CTI manager
+	CTIConnectionActive = "CTIConnectionActive"
+	LinesOpen           = "LinesOpen"
+	DevicesOpen         = "DevicesOpen"
+	CcmLinkActive       = "CcmLinkActive"
+	RequestsCompleted   = "RequestsCompleted"
+	RequestsInProgress  = "RequestsInProgress"
+	QbeVersion          = "QbeVersion"
+
+	// CallsAbandoned hunt lists
+	CallsAbandoned       = "CallsAbandoned"
+	CallsRanOutOfMembers = "CallsRanOutOfMembers"
+	CallsRingNoAnswer    = "CallsRingNoAnswer"
+	MembersAvailable     = "MembersAvailable"
+	HuntListInService    = "HuntListInService"
+
+	// CallsAbandonedInQueue hunt pilots
+	CallsAbandonedInQueue = "CallsAbandonedInQueue"
+	CallsInQueue          = "CallsInQueue"
+
+	// MOHHighestActiveResources MOH device
+	MOHHighestActiveResources = "MOHHighestActiveResources"
+	MOHConnectionState        = "MOHConnectionState"
+
+	// ANNStreamsActive media streaming application
+	ANNStreamsActive     = "ANNStreamsActive"
+	ANNStreamsAvailable  = "ANNStreamsAvailable"
+	ANNConnectionsLost   = "ANNConnectionsLost"
+	CFBConferencesActive = "CFBConferencesActive"
+	CFBStreamsActive     = "CFBStreamsActive"
+	CFBStreamsAvailable  = "CFBStreamsAvailable"
+	CFBConnectionsLost   = "CFBConnectionsLost"
+	MOHStreamsActive     = "MOHStreamsActive"
+	MOHStreamsAvailable  = "MOHStreamsAvailable"
+	MOHConnectionsLost   = "MOHConnectionsLost"
+	MTPInstancesActive   = "MTPInstancesActive"
+	MTPConnectionsLost   = "MTPConnectionsLost"
+
+	// AllocatedResourceCannotOpenPort MTP device
+	AllocatedResourceCannotOpenPort = "AllocatedResourceCannotOpenPort"
+
+	// ReplicateState DB replication
+	ReplicateState            = "Replicate_State"
+	NumberOfReplicatesCreated = "Number of Replicates Created"
+
+	// CcmDbSpaceUsed database
+	CcmDbSpaceUsed     = "CcmDbSpace_Used"
+	CcmtempDbSpaceUsed = "CcmtempDbSpace_Used"
+	CNDbSpaceUsed      = "CNDbSpace_Used"
+	RootDbSpaceUsed    = "RootDbSpace_Used"
+	SharedMemoryFree   = "SharedMemory_Free"
+	SharedMemoryUsed   = "SharedMemory_Used"
+
+	// QueuedRequestsInDB DB change notification
+	QueuedRequestsInDB     = "QueuedRequestsInDB"
+	QueuedRequestsInMemory = "QueuedRequestsInMemory"
+	QueueDelay             = "QueueDelay"
+	CNProcessed            = "CNProcessed"
+
+	// ThreadsBusy Tomcat connector
+	ThreadsBusy    = "ThreadsBusy"
+	ThreadsTotal   = "ThreadsTotal"
+	ThreadsMax     = "ThreadsMax"
+	Requests       = "Requests"
+	Errors         = "Errors"
+	MBytesReceived = "MBytesReceived"
+	MBytesSent     = "MBytesSent"
+
+	// KBytesMemoryFree Tomcat JVM
+	KBytesMemoryFree  = "KBytesMemoryFree"
+	KBytesMemoryTotal = "KBytesMemoryTotal"
+	KBytesMemoryMax   = "KBytesMemoryMax"
+
+	// SessionsActive Tomcat web application
+	SessionsActive = "SessionsActive"
+
+	// RequestsProcessed TFTP server
+	RequestsProcessed     = "RequestsProcessed"
+	RequestsNotFound      = "RequestsNotFound"
+	RequestsAborted       = "RequestsAborted"
+	RequestsOverflow      = "RequestsOverflow"
+	HttpRequestsProcessed = "HttpRequestsProcessed"
+	HttpRequestsNotFound  = "HttpRequestsNotFound"
+	HttpRequestsAborted   = "HttpRequestsAborted"
+	BuildAbortCount       = "BuildAbortCount"
+	BuildDuration         = "BuildDuration"
+	HeartBeat             = "HeartBeat"
+
+	// LoginsSuccessful extension mobility
+	LoginsSuccessful   = "LoginsSuccessful"
+	LogoutsSuccessful  = "LogoutsSuccessful"
+	RequestsHandled    = "RequestsHandled"
+	RequestsFailed     = "RequestsFailed"
+	RequestsThrottled  = "RequestsThrottled"
+	AverageServiceTime = "AverageServiceTime"
+
+	// SessionsCurrent Unity Connection voice sessions
+	SessionsCurrent      = "Sessions - Current"
+	SessionsTotal        = "Sessions - Total"
+	DelaySubscriberLogon = "Delay - Subscriber Logon [ms]"
+
+	// MessagesDeliveredTotal Unity Connection message storage
+	MessagesDeliveredTotal = "Messages Delivered - Total"
+	QueuedMessagesCurrent  = "Queued Messages - Current"
+	MessageSizeAverage     = "Message Size Average [kilobytes]"
+
+	// PortsInUseCurrent Unity Connection phone system
+	PortsInUseCurrent = "Ports In Use - Current"
+	PortsIdleCurrent  = "Ports Idle - Current"
+	PortsLocked       = "Ports Locked"
+	CallCountTotal    = "Call Count - Total"
+
+	// JsmSessions IM and Presence XCP JSM
+	JsmSessions   = "JsmSessions"
+	JsmIMSessions = "JsmIMSessions"
+
+	// ActiveSubscriptions IM and Presence engine
+	ActiveSubscriptions = "ActiveSubscriptions"
+	SubscribesReceived  = "SubscribesReceived"
+
+	// NumSipdWorker IM and Presence SIP proxy
+	NumSipdWorker      = "NumSipdWorker"
+	NumIdleSipdWorkers = "NumIdleSipdWorkers"
+	SIPRetransmits     = "SIPRetransmits"
+
+	// ResourceActive common media device counters
+	ResourceActive    = "ResourceActive"
+	ResourceAvailable = "ResourceAvailable"
+	ResourceTotal     = "ResourceTotal"
 )
 
 var (
@@ -158,8 +374,6 @@ var (
 		{allowedCounterName: AuthenticatedCallsCompleted, prometheusName: "cucm_authenticated_calls_completed", defaultEnabled: false},
 		{allowedCounterName: AuthenticatedPartiallyRegisteredPhone, prometheusName: "cucm_authenticated_partially_registeredPhone", defaultEnabled: false},
 		{allowedCounterName: AuthenticatedRegisteredPhones, prometheusName: "cucm_authenticated_registered_phones", defaultEnabled: false},
-		//{allowedCounterName: BRIChannelsActive, prometheusName: "cucm_bri_channels_active", defaultEnabled: false},
-		//{allowedCounterName: BRISpansInService, prometheusName: "cucm_bri_spans_in_service", defaultEnabled: false},
 		{allowedCounterName: CallManagerHeartBeat, prometheusName: "cucm_call_manager_heart_beat", defaultEnabled: false},
 		{allowedCounterName: CumulativeAllocatedResourceCannotOpenPort, prometheusName: "cucm_cumulative_allocated_resource_cannot_open_port", defaultEnabled: false},
 		{allowedCounterName: EncryptedCallsActive, prometheusName: "cucm_encrypted_calls_active", defaultEnabled: false},
@@ -224,6 +438,17 @@ var (
 		{allowedCounterName: SWConferenceResourceActive, prometheusName: "cucm_sw_conference_resource_active", defaultEnabled: false},
 		{allowedCounterName: SWConferenceResourceAvailable, prometheusName: "cucm_sw_conference_resource_available", defaultEnabled: false},
 		{allowedCounterName: SWConferenceResourceTotal, prometheusName: "cucm_sw_conference_resource_total", defaultEnabled: false},
+		// gateway channels and spans
+		{allowedCounterName: BRIChannelsActive, prometheusName: "cucm_bri_channels_active", defaultEnabled: false},
+		{allowedCounterName: BRISpansInService, prometheusName: "cucm_bri_spans_in_service", defaultEnabled: false},
+		{allowedCounterName: FXOPortsActive, prometheusName: "cucm_fxo_ports_active", defaultEnabled: false},
+		{allowedCounterName: FXOPortsInService, prometheusName: "cucm_fxo_ports_in_service", defaultEnabled: false},
+		{allowedCounterName: FXSPortsActive, prometheusName: "cucm_fxs_ports_active", defaultEnabled: false},
+		{allowedCounterName: FXSPortsInService, prometheusName: "cucm_fxs_ports_in_service", defaultEnabled: false},
+		{allowedCounterName: PRIChannelsActive, prometheusName: "cucm_pri_channels_active", defaultEnabled: false},
+		{allowedCounterName: PRISpansInService, prometheusName: "cucm_pri_spans_in_service", defaultEnabled: false},
+		{allowedCounterName: T1ChannelsActive, prometheusName: "cucm_t1_channels_active", defaultEnabled: false},
+		{allowedCounterName: T1SpansInService, prometheusName: "cucm_t1_spans_in_service", defaultEnabled: false},
 		// registered info
 		{allowedCounterName: RegisteredAnalogAccess, prometheusName: "cucm_registered_analog_access", defaultEnabled: false},
 		{allowedCounterName: RegisteredMGCPGateway, prometheusName: "cucm_registered_mgcp_gateway", defaultEnabled: false},
@@ -240,3 +465,447 @@ func isNameInAllowedCounter(name string) bool {
 	}
 	return false
 }
+
+// supportedCounter find supported counter definition by name
+func supportedCounter(name string) *Counters {
+	for i := range SupportedCounters {
+		if SupportedCounters[i].allowedCounterName == name {
+			return &SupportedCounters[i]
+		}
+	}
+	return nil
+}
+
+// standardGroup is group exported with standard counters (SupportedCounters)
+//   - group of enabled object isn't standard, counters are exported only once as object metrics
+func standardGroup(group string) bool {
+	if !inSlice(group, AllowedGroupNames) {
+		return false
+	}
+	object := supportedObject(group)
+	return object == nil || !object.enabled()
+}
+
+// isCounterCollected counter is added to session when is exported or required for computed metrics
+func isCounterCollected(name string) bool {
+	if config.Metrics.enablePrometheusCounter(name) {
+		return true
+	}
+	if config.BusyHour.Enabled && (name == CallsAttempted || name == CallsActive) {
+		return true
+	}
+	for _, rule := range config.Aggregation {
+		if rule.Counter == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ObjectCounters PerfMon object exported with own counters, multi-instance objects are exported with instance label
+type ObjectCounters struct {
+	groupName     string     // groupName PerfMon object name
+	configName    string     // configName name of object in configuration section objects
+	instanceLabel string     // instanceLabel Prometheus label name for instance of multi-instance object, empty for single instance object
+	counters      []Counters // counters supported counters of object
+}
+
+var (
+	SupportedObjects = []ObjectCounters{
+		{groupName: SipTrunkGroup, configName: "sip", instanceLabel: "trunk", counters: []Counters{
+			{allowedCounterName: CallsActive, prometheusName: "cucm_sip_trunk_calls_active", defaultEnabled: true},
+			{allowedCounterName: CallsAttempted, prometheusName: "cucm_sip_trunk_calls_attempted_total", defaultEnabled: true, cumulative: true, required: true},
+			{allowedCounterName: CallsCompleted, prometheusName: "cucm_sip_trunk_calls_completed_total", defaultEnabled: true, cumulative: true, required: true},
+			{allowedCounterName: CallsInProgress, prometheusName: "cucm_sip_trunk_calls_in_progress", defaultEnabled: true, required: true},
+			{allowedCounterName: VideoCallsActive, prometheusName: "cucm_sip_trunk_video_calls_active", defaultEnabled: false},
+			{allowedCounterName: VideoCallsCompleted, prometheusName: "cucm_sip_trunk_video_calls_completed_total", defaultEnabled: false, cumulative: true},
+		}},
+		// MGCP gateways
+		{groupName: "Cisco MGCP Gateways", configName: "mgcpGateways", instanceLabel: "gateway", counters: []Counters{
+			{allowedCounterName: BRIChannelsActive, prometheusName: "cucm_mgcp_gateway_bri_channels_active", defaultEnabled: true},
+			{allowedCounterName: BRISpansInService, prometheusName: "cucm_mgcp_gateway_bri_spans_in_service", defaultEnabled: true},
+			{allowedCounterName: FXOPortsActive, prometheusName: "cucm_mgcp_gateway_fxo_ports_active", defaultEnabled: true},
+			{allowedCounterName: FXOPortsInService, prometheusName: "cucm_mgcp_gateway_fxo_ports_in_service", defaultEnabled: true},
+			{allowedCounterName: FXSPortsActive, prometheusName: "cucm_mgcp_gateway_fxs_ports_active", defaultEnabled: true},
+			{allowedCounterName: FXSPortsInService, prometheusName: "cucm_mgcp_gateway_fxs_ports_in_service", defaultEnabled: true},
+			{allowedCounterName: PRIChannelsActive, prometheusName: "cucm_mgcp_gateway_pri_channels_active", defaultEnabled: true},
+			{allowedCounterName: PRISpansInService, prometheusName: "cucm_mgcp_gateway_pri_spans_in_service", defaultEnabled: true},
+			{allowedCounterName: T1ChannelsActive, prometheusName: "cucm_mgcp_gateway_t1_channels_active", defaultEnabled: true},
+			{allowedCounterName: T1SpansInService, prometheusName: "cucm_mgcp_gateway_t1_spans_in_service", defaultEnabled: true},
+		}},
+		{groupName: "Cisco MGCP PRI Device", configName: "mgcpPri", instanceLabel: "device", counters: objectCounters([]Counters{
+			{allowedCounterName: CallsActive, prometheusName: "cucm_mgcp_pri_calls_active", defaultEnabled: true},
+			{allowedCounterName: CallsCompleted, prometheusName: "cucm_mgcp_pri_calls_completed_total", defaultEnabled: true, cumulative: true},
+			{allowedCounterName: DatalinkInService, prometheusName: "cucm_mgcp_pri_datalink_in_service", defaultEnabled: true},
+			{allowedCounterName: OutboundBusyAttempts, prometheusName: "cucm_mgcp_pri_outbound_busy_attempts_total", defaultEnabled: true, cumulative: true},
+		}, channelCounters("cucm_mgcp_pri_channels", 31))},
+		{groupName: "Cisco MGCP T1CAS Device", configName: "mgcpT1Cas", instanceLabel: "device", counters: objectCounters([]Counters{
+			{allowedCounterName: CallsActive, prometheusName: "cucm_mgcp_t1cas_calls_active", defaultEnabled: true},
+			{allowedCounterName: CallsCompleted, prometheusName: "cucm_mgcp_t1cas_calls_completed_total", defaultEnabled: true, cumulative: true},
+			{allowedCounterName: OutboundBusyAttempts, prometheusName: "cucm_mgcp_t1cas_outbound_busy_attempts_total", defaultEnabled: true, cumulative: true},
+		}, channelCounters("cucm_mgcp_t1cas_channels", 24))},
+		{groupName: "Cisco MGCP BRI Device", configName: "mgcpBri", instanceLabel: "device", counters: objectCounters([]Counters{
+			{allowedCounterName: CallsActive, prometheusName: "cucm_mgcp_bri_calls_active", defaultEnabled: true},
+			{allowedCounterName: CallsCompleted, prometheusName: "cucm_mgcp_bri_calls_completed_total", defaultEnabled: true, cumulative: true},
+			{allowedCounterName: DatalinkInService, prometheusName: "cucm_mgcp_bri_datalink_in_service", defaultEnabled: true},
+			{allowedCounterName: OutboundBusyAttempts, prometheusName: "cucm_mgcp_bri_outbound_busy_attempts_total", defaultEnabled: true, cumulative: true},
+		}, channelCounters("cucm_mgcp_bri_channels", 2))},
+		{groupName: "Cisco MGCP FXO Device", configName: "mgcpFxo", instanceLabel: "device", counters: []Counters{
+			{allowedCounterName: CallsCompleted, prometheusName: "cucm_mgcp_fxo_calls_completed_total", defaultEnabled: true, cumulative: true},
+			{allowedCounterName: OutboundBusyAttempts, prometheusName: "cucm_mgcp_fxo_outbound_busy_attempts_total", defaultEnabled: true, cumulative: true},
+			{allowedCounterName: PortStatus, prometheusName: "cucm_mgcp_fxo_ports", defaultEnabled: true, channel: 1},
+		}},
+		{groupName: "Cisco MGCP FXS Device", configName: "mgcpFxs", instanceLabel: "device", counters: []Counters{
+			{allowedCounterName: CallsCompleted, prometheusName: "cucm_mgcp_fxs_calls_completed_total", defaultEnabled: true, cumulative: true},
+			{allowedCounterName: OutboundBusyAttempts, prometheusName: "cucm_mgcp_fxs_outbound_busy_attempts_total", defaultEnabled: true, cumulative: true},
+			{allowedCounterName: PortStatus, prometheusName: "cucm_mgcp_fxs_ports", defaultEnabled: true, channel: 1},
+		}},
+		// host OS resources
+		{groupName: "Processor", configName: "processor", instanceLabel: "cpu", counters: []Counters{
+			{allowedCounterName: CPUTime, prometheusName: "cucm_processor_cpu_time_percent", defaultEnabled: true, rate: true},
+			{allowedCounterName: UserPercentage, prometheusName: "cucm_processor_user_percent", defaultEnabled: true, rate: true},
+			{allowedCounterName: SystemPercentage, prometheusName: "cucm_processor_system_percent", defaultEnabled: true, rate: true},
+			{allowedCounterName: IOwaitPercentage, prometheusName: "cucm_processor_iowait_percent", defaultEnabled: true, rate: true},
+			{allowedCounterName: IdlePercentage, prometheusName: "cucm_processor_idle_percent", defaultEnabled: false, rate: true},
+			{allowedCounterName: NicePercentage, prometheusName: "cucm_processor_nice_percent", defaultEnabled: false, rate: true},
+			{allowedCounterName: IrqPercentage, prometheusName: "cucm_processor_irq_percent", defaultEnabled: false, rate: true},
+			{allowedCounterName: SoftirqPercentage, prometheusName: "cucm_processor_softirq_percent", defaultEnabled: false, rate: true},
+		}},
+		{groupName: "Memory", configName: "memory", instanceLabel: "instance", counters: []Counters{
+			{allowedCounterName: MemUsed, prometheusName: "cucm_memory_used_percent", defaultEnabled: true},
+			{allowedCounterName: VMUsed, prometheusName: "cucm_memory_vm_used_percent", defaultEnabled: true},
+			{allowedCounterName: PageUsage, prometheusName: "cucm_memory_page_usage_percent", defaultEnabled: false},
+			{allowedCounterName: TotalKBytes, prometheusName: "cucm_memory_total_bytes", defaultEnabled: true, scale: ScaleKilobytes},
+			{allowedCounterName: UsedKBytes, prometheusName: "cucm_memory_used_bytes", defaultEnabled: true, scale: ScaleKilobytes},
+			{allowedCounterName: FreeKBytes, prometheusName: "cucm_memory_free_bytes", defaultEnabled: true, scale: ScaleKilobytes},
+			{allowedCounterName: BuffersKBytes, prometheusName: "cucm_memory_buffers_bytes", defaultEnabled: false, scale: ScaleKilobytes},
+			{allowedCounterName: CachedKBytes, prometheusName: "cucm_memory_cached_bytes", defaultEnabled: false, scale: ScaleKilobytes},
+			{allowedCounterName: TotalSwapKBytes, prometheusName: "cucm_memory_swap_total_bytes", defaultEnabled: true, scale: ScaleKilobytes},
+			{allowedCounterName: UsedSwapKBytes, prometheusName: "cucm_memory_swap_used_bytes", defaultEnabled: true, scale: ScaleKilobytes},
+			{allowedCounterName: FreeSwapKBytes, prometheusName: "cucm_memory_swap_free_bytes", defaultEnabled: false, scale: ScaleKilobytes},
+		}},
+		{groupName: "Partition", configName: "partition", instanceLabel: "partition", counters: []Counters{
+			{allowedCounterName: PartitionUsed, prometheusName: "cucm_partition_used_percent", defaultEnabled: true},
+			{allowedCounterName: TotalMbytes, prometheusName: "cucm_partition_total_bytes", defaultEnabled: true, scale: ScaleMegabytes},
+			{allowedCounterName: UsedMbytes, prometheusName: "cucm_partition_used_bytes", defaultEnabled: true, scale: ScaleMegabytes},
+			{allowedCounterName: CPUTime, prometheusName: "cucm_partition_cpu_time_percent", defaultEnabled: false, rate: true},
+			{allowedCounterName: QueueLength, prometheusName: "cucm_partition_queue_length", defaultEnabled: false},
+			{allowedCounterName: ReadBytesPerSec, prometheusName: "cucm_partition_read_bytes_per_second", defaultEnabled: false, rate: true},
+			{allowedCounterName: WriteBytesPerSec, prometheusName: "cucm_partition_write_bytes_per_second", defaultEnabled: false, rate: true},
+		}},
+		// call admission control
+		{groupName: LocationHeadroomGroup, configName: "locationsLbm", instanceLabel: "location", counters: []Counters{
+			{allowedCounterName: BandwidthAvailable, prometheusName: "cucm_location_bandwidth_available_bits_per_second", defaultEnabled: true, scale: ScaleKilobits, required: true},
+			{allowedCounterName: BandwidthMaximum, prometheusName: "cucm_location_bandwidth_maximum_bits_per_second", defaultEnabled: true, scale: ScaleKilobits, required: true},
+			{allowedCounterName: BandwidthOversubscription, prometheusName: "cucm_location_bandwidth_oversubscription_bits_per_second", defaultEnabled: false, scale: ScaleKilobits},
+			{allowedCounterName: CallsInProgress, prometheusName: "cucm_location_calls_in_progress", defaultEnabled: true},
+			{allowedCounterName: OutOfResources, prometheusName: "cucm_location_out_of_resources_total", defaultEnabled: true, cumulative: true},
+			{allowedCounterName: VideoBandwidthAvailable, prometheusName: "cucm_location_video_bandwidth_available_bits_per_second", defaultEnabled: true, scale: ScaleKilobits, required: true},
+			{allowedCounterName: VideoBandwidthMaximum, prometheusName: "cucm_location_video_bandwidth_maximum_bits_per_second", defaultEnabled: true, scale: ScaleKilobits, required: true},
+			{allowedCounterName: VideoOutOfResources, prometheusName: "cucm_location_video_out_of_resources_total", defaultEnabled: true, cumulative: true},
+			{allowedCounterName: ImmersiveVideoBandwidthAvailable, prometheusName: "cucm_location_immersive_bandwidth_available_bits_per_second", defaultEnabled: false, scale: ScaleKilobits, required: true},
+			{allowedCounterName: ImmersiveVideoBandwidthMaximum, prometheusName: "cucm_location_immersive_bandwidth_maximum_bits_per_second", defaultEnabled: false, scale: ScaleKilobits, required: true},
+			{allowedCounterName: ImmersiveOutOfResources, prometheusName: "cucm_location_immersive_out_of_resources_total", defaultEnabled: false, cumulative: true},
+		}},
+		{groupName: "Cisco Locations RSVP", configName: "locationsRsvp", instanceLabel: "location", counters: []Counters{
+			{allowedCounterName: RSVPMandatoryConnectionsInProgress, prometheusName: "cucm_location_rsvp_mandatory_calls_in_progress", defaultEnabled: true},
+			{allowedCounterName: RSVPOptionalConnectionsInProgress, prometheusName: "cucm_location_rsvp_optional_calls_in_progress", defaultEnabled: true},
+			{allowedCounterName: RSVPTotalCallsFailed, prometheusName: "cucm_location_rsvp_calls_failed_total", defaultEnabled: true, cumulative: true},
+			{allowedCounterName: RSVPVideoCallsFailed, prometheusName: "cucm_location_rsvp_video_calls_failed_total", defaultEnabled: true, cumulative: true},
+			{allowedCounterName: RSVPAudioReservationErrorCounts, prometheusName: "cucm_location_rsvp_audio_reservation_errors_total", defaultEnabled: false, cumulative: true},
+			{allowedCounterName: RSVPVideoReservationErrorCounts, prometheusName: "cucm_location_rsvp_video_reservation_errors_total", defaultEnabled: false, cumulative: true},
+		}},
+		// computer telephony integration, single instance object, PerfMon doesn't provide counters per CTI provider
+		{groupName: "Cisco CTIManager", configName: "ctiManager", counters: []Counters{
+			{allowedCounterName: CTIConnectionActive, prometheusName: "cucm_cti_connections_active", defaultEnabled: true},
+			{allowedCounterName: LinesOpen, prometheusName: "cucm_cti_lines_open", defaultEnabled: true},
+			{allowedCounterName: DevicesOpen, prometheusName: "cucm_cti_devices_open", defaultEnabled: true},
+			{allowedCounterName: CcmLinkActive, prometheusName: "cucm_cti_ccm_links_active", defaultEnabled: true},
+			{allowedCounterName: RequestsCompleted, prometheusName: "cucm_cti_requests_completed_total", defaultEnabled: true, cumulative: true},
+			{allowedCounterName: RequestsInProgress, prometheusName: "cucm_cti_requests_in_progress", defaultEnabled: true},
+			{allowedCounterName: QbeVersion, prometheusName: "cucm_cti_qbe_version", defaultEnabled: false},
+		}},
+		// hunting
+		{groupName: "Cisco Hunt Lists", configName: "huntLists", instanceLabel: "hunt_list", counters: []Counters{
+			{allowedCounterName: CallsActive, prometheusName: "cucm_hunt_list_calls_active", defaultEnabled: true},
+			{allowedCounterName: CallsAbandoned, prometheusName: "cucm_hunt_list_calls_abandoned_total", defaultEnabled: true, cumulative: true},
+			{allowedCounterName: CallsRanOutOfMembers, prometheusName: "cucm_hunt_list_calls_ran_out_of_members_total", defaultEnabled: true, cumulative: true},
+			{allowedCounterName: CallsRingNoAnswer, prometheusName: "cucm_hunt_list_calls_ring_no_answer_total", defaultEnabled: false, cumulative: true},
+			{allowedCounterName: MembersAvailable, prometheusName: "cucm_hunt_list_members_available", defaultEnabled: true},
+			{allowedCounterName: HuntListInService, prometheusName: "cucm_hunt_list_in_service", defaultEnabled: true},
+		}},
+		{groupName: "Cisco Hunt Pilots", configName: "huntPilots", instanceLabel: "hunt_pilot", counters: []Counters{
+			{allowedCounterName: CallsActive, prometheusName: "cucm_hunt_pilot_calls_active", defaultEnabled: true},
+			{allowedCounterName: CallsAbandoned, prometheusName: "cucm_hunt_pilot_calls_abandoned_total", defaultEnabled: true, cumulative: true},
+			{allowedCounterName: CallsAbandonedInQueue, prometheusName: "cucm_hunt_pilot_calls_abandoned_in_queue_total", defaultEnabled: true, cumulative: true},
+			{allowedCounterName: CallsRanOutOfMembers, prometheusName: "cucm_hunt_pilot_calls_ran_out_of_members_total", defaultEnabled: true, cumulative: true},
+			{allowedCounterName: CallsInQueue, prometheusName: "cucm_hunt_pilot_calls_in_queue", defaultEnabled: true},
+			{allowedCounterName: MembersAvailable, prometheusName: "cucm_hunt_pilot_members_available", defaultEnabled: true},
+		}},
+		// media resources per device
+		{groupName: "Cisco MOH Device", configName: "mohDevice", instanceLabel: "device", counters: []Counters{
+			{allowedCounterName: MOHUnicastResourceActive, prometheusName: "cucm_moh_device_unicast_resource_active", defaultEnabled: true},
+			{allowedCounterName: MOHUnicastResourceAvailable, prometheusName: "cucm_moh_device_unicast_resource_available", defaultEnabled: true},
+			{allowedCounterName: MOHTotalUnicastResources, prometheusName: "cucm_moh_device_unicast_resource_total", defaultEnabled: true},
+			{allowedCounterName: MOHMulticastResourceActive, prometheusName: "cucm_moh_device_multicast_resource_active", defaultEnabled: true},
+			{allowedCounterName: MOHMulticastResourceAvailable, prometheusName: "cucm_moh_device_multicast_resource_available", defaultEnabled: true},
+			{allowedCounterName: MOHTotalMulticastResources, prometheusName: "cucm_moh_device_multicast_resource_total", defaultEnabled: true},
+			{allowedCounterName: MOHHighestActiveResources, prometheusName: "cucm_moh_device_highest_active_resources", defaultEnabled: false},
+			{allowedCounterName: MOHOutOfResources, prometheusName: "cucm_moh_device_out_of_resources_total", defaultEnabled: true, cumulative: true},
+			{allowedCounterName: MOHConnectionState, prometheusName: "cucm_moh_device_connection_state", defaultEnabled: true},
+		}},
+		{groupName: "Cisco Media Streaming App", configName: "mediaStreamingApp", instanceLabel: "device", counters: []Counters{
+			{allowedCounterName: ANNStreamsActive, prometheusName: "cucm_media_streaming_ann_streams_active", defaultEnabled: true},
+			{allowedCounterName: ANNStreamsAvailable, prometheusName: "cucm_media_streaming_ann_streams_available", defaultEnabled: true},
+			{allowedCounterName: ANNConnectionsLost, prometheusName: "cucm_media_streaming_ann_connections_lost_total", defaultEnabled: true, cumulative: true},
+			{allowedCounterName: CFBConferencesActive, prometheusName: "cucm_media_streaming_cfb_conferences_active", defaultEnabled: true},
+			{allowedCounterName: CFBStreamsActive, prometheusName: "cucm_media_streaming_cfb_streams_active", defaultEnabled: true},
+			{allowedCounterName: CFBStreamsAvailable, prometheusName: "cucm_media_streaming_cfb_streams_available", defaultEnabled: true},
+			{allowedCounterName: CFBConnectionsLost, prometheusName: "cucm_media_streaming_cfb_connections_lost_total", defaultEnabled: true, cumulative: true},
+			{allowedCounterName: MOHStreamsActive, prometheusName: "cucm_media_streaming_moh_streams_active", defaultEnabled: true},
+			{allowedCounterName: MOHStreamsAvailable, prometheusName: "cucm_media_streaming_moh_streams_available", defaultEnabled: true},
+			{allowedCounterName: MOHConnectionsLost, prometheusName: "cucm_media_streaming_moh_connections_lost_total", defaultEnabled: true, cumulative: true},
+			{allowedCounterName: MTPInstancesActive, prometheusName: "cucm_media_streaming_mtp_instances_active", defaultEnabled: true},
+			{allowedCounterName: MTPConnectionsLost, prometheusName: "cucm_media_streaming_mtp_connections_lost_total", defaultEnabled: true, cumulative: true},
+		}},
+		{groupName: "Cisco HW Conference Bridge Device", configName: "hwConferenceDevice", instanceLabel: "device", counters: objectCounters([]Counters{
+			{allowedCounterName: HWConferenceActive, prometheusName: "cucm_hw_conference_device_conferences_active", defaultEnabled: true},
+			{allowedCounterName: HWConferenceCompleted, prometheusName: "cucm_hw_conference_device_conferences_completed_total", defaultEnabled: true, cumulative: true},
+		}, resourceCounters("cucm_hw_conference_device"))},
+		{groupName: "Cisco Transcode Device", configName: "transcodeDevice", instanceLabel: "device", counters: resourceCounters("cucm_transcode_device")},
+		{groupName: "Cisco MTP Device", configName: "mtpDevice", instanceLabel: "device", counters: objectCounters([]Counters{
+			{allowedCounterName: AllocatedResourceCannotOpenPort, prometheusName: "cucm_mtp_device_cannot_open_port_total", defaultEnabled: false, cumulative: true},
+		}, resourceCounters("cucm_mtp_device"))},
+		// compliance recording, enabled object replace standard recording metrics of group Cisco Recording
+		{groupName: "Cisco Recording", configName: "recording", instanceLabel: "profile", counters: []Counters{
+			{allowedCounterName: GatewaysSessionsActive, prometheusName: "cucm_recording_gateway_sessions_active", defaultEnabled: true},
+			{allowedCounterName: GatewaysSessionsFailed, prometheusName: "cucm_recording_gateway_sessions_failed_total", defaultEnabled: true, cumulative: true},
+			{allowedCounterName: PhoneSessionsActive, prometheusName: "cucm_recording_phone_sessions_active", defaultEnabled: true},
+			{allowedCounterName: PhoneSessionsFailed, prometheusName: "cucm_recording_phone_sessions_failed_total", defaultEnabled: true, cumulative: true},
+		}},
+		// database
+		{groupName: "Number of Replicates Created and State of Replication", configName: "dbReplication", instanceLabel: "instance", counters: []Counters{
+			{allowedCounterName: ReplicateState, prometheusName: "cucm_db_replication_state", defaultEnabled: true, states: ReplicationStateNames},
+			{allowedCounterName: NumberOfReplicatesCreated, prometheusName: "cucm_db_replicates_created", defaultEnabled: true},
+		}},
+		{groupName: "Cisco DB", configName: "db", instanceLabel: "instance", counters: []Counters{
+			{allowedCounterName: CcmDbSpaceUsed, prometheusName: "cucm_db_ccm_dbspace_used", defaultEnabled: true},
+			{allowedCounterName: CcmtempDbSpaceUsed, prometheusName: "cucm_db_ccmtemp_dbspace_used", defaultEnabled: false},
+			{allowedCounterName: CNDbSpaceUsed, prometheusName: "cucm_db_cn_dbspace_used", defaultEnabled: true},
+			{allowedCounterName: RootDbSpaceUsed, prometheusName: "cucm_db_root_dbspace_used", defaultEnabled: true},
+			{allowedCounterName: SharedMemoryFree, prometheusName: "cucm_db_shared_memory_free_bytes", defaultEnabled: false, scale: ScaleKilobytes},
+			{allowedCounterName: SharedMemoryUsed, prometheusName: "cucm_db_shared_memory_used_bytes", defaultEnabled: false, scale: ScaleKilobytes},
+		}},
+		{groupName: "Cisco DB Change Notification Server", configName: "dbChangeNotification", instanceLabel: "instance", counters: []Counters{
+			{allowedCounterName: QueuedRequestsInDB, prometheusName: "cucm_db_change_notification_queued_requests_db", defaultEnabled: true},
+			{allowedCounterName: QueuedRequestsInMemory, prometheusName: "cucm_db_change_notification_queued_requests_memory", defaultEnabled: true},
+			{allowedCounterName: QueueDelay, prometheusName: "cucm_db_change_notification_queue_delay_seconds", defaultEnabled: true},
+			{allowedCounterName: CNProcessed, prometheusName: "cucm_db_change_notification_processed_total", defaultEnabled: true, cumulative: true},
+		}},
+		// web services
+		{groupName: "Cisco Tomcat Connector", configName: "tomcatConnector", instanceLabel: "connector", counters: []Counters{
+			{allowedCounterName: ThreadsBusy, prometheusName: "cucm_tomcat_connector_threads_busy", defaultEnabled: true},
+			{allowedCounterName: ThreadsTotal, prometheusName: "cucm_tomcat_connector_threads", defaultEnabled: true},
+			{allowedCounterName: ThreadsMax, prometheusName: "cucm_tomcat_connector_threads_max", defaultEnabled: true},
+			{allowedCounterName: Requests, prometheusName: "cucm_tomcat_connector_requests_total", defaultEnabled: true, cumulative: true},
+			{allowedCounterName: Errors, prometheusName: "cucm_tomcat_connector_errors_total", defaultEnabled: true, cumulative: true},
+			{allowedCounterName: MBytesReceived, prometheusName: "cucm_tomcat_connector_received_bytes_total", defaultEnabled: false, cumulative: true, scale: ScaleMegabytes},
+			{allowedCounterName: MBytesSent, prometheusName: "cucm_tomcat_connector_sent_bytes_total", defaultEnabled: false, cumulative: true, scale: ScaleMegabytes},
+		}},
+		{groupName: "Cisco Tomcat JVM", configName: "tomcatJvm", instanceLabel: "instance", counters: []Counters{
+			{allowedCounterName: KBytesMemoryFree, prometheusName: "cucm_tomcat_jvm_memory_free_bytes", defaultEnabled: true, scale: ScaleKilobytes},
+			{allowedCounterName: KBytesMemoryTotal, prometheusName: "cucm_tomcat_jvm_memory_total_bytes", defaultEnabled: true, scale: ScaleKilobytes},
+			{allowedCounterName: KBytesMemoryMax, prometheusName: "cucm_tomcat_jvm_memory_max_bytes", defaultEnabled: true, scale: ScaleKilobytes},
+		}},
+		{groupName: "Cisco Tomcat Web Application", configName: "tomcatWebApplication", instanceLabel: "application", counters: []Counters{
+			{allowedCounterName: Requests, prometheusName: "cucm_tomcat_application_requests_total", defaultEnabled: true, cumulative: true},
+			{allowedCounterName: Errors, prometheusName: "cucm_tomcat_application_errors_total", defaultEnabled: true, cumulative: true},
+			{allowedCounterName: SessionsActive, prometheusName: "cucm_tomcat_application_sessions_active", defaultEnabled: true},
+		}},
+		// device configuration download
+		{groupName: "Cisco TFTP", configName: "tftp", instanceLabel: "instance", counters: []Counters{
+			{allowedCounterName: Requests, prometheusName: "cucm_tftp_requests_total", defaultEnabled: true, cumulative: true},
+			{allowedCounterName: RequestsProcessed, prometheusName: "cucm_tftp_requests_processed_total", defaultEnabled: true, cumulative: true},
+			{allowedCounterName: RequestsNotFound, prometheusName: "cucm_tftp_requests_not_found_total", defaultEnabled: true, cumulative: true},
+			{allowedCounterName: RequestsAborted, prometheusName: "cucm_tftp_requests_aborted_total", defaultEnabled: true, cumulative: true},
+			{allowedCounterName: RequestsOverflow, prometheusName: "cucm_tftp_requests_overflow_total", defaultEnabled: false, cumulative: true},
+			{allowedCounterName: RequestsInProgress, prometheusName: "cucm_tftp_requests_in_progress", defaultEnabled: true},
+			{allowedCounterName: HttpRequestsProcessed, prometheusName: "cucm_tftp_http_requests_processed_total", defaultEnabled: false, cumulative: true},
+			{allowedCounterName: HttpRequestsNotFound, prometheusName: "cucm_tftp_http_requests_not_found_total", defaultEnabled: false, cumulative: true},
+			{allowedCounterName: HttpRequestsAborted, prometheusName: "cucm_tftp_http_requests_aborted_total", defaultEnabled: false, cumulative: true},
+			{allowedCounterName: TftpBuildCount, prometheusName: "cucm_tftp_builds_total", defaultEnabled: true, cumulative: true},
+			{allowedCounterName: BuildAbortCount, prometheusName: "cucm_tftp_builds_aborted_total", defaultEnabled: true, cumulative: true},
+			{allowedCounterName: BuildDuration, prometheusName: "cucm_tftp_build_duration_seconds", defaultEnabled: true},
+			{allowedCounterName: TftpBuildDeviceCount, prometheusName: "cucm_tftp_build_devices", defaultEnabled: true},
+			{allowedCounterName: TftpBuildUnitCount, prometheusName: "cucm_tftp_build_units", defaultEnabled: false},
+			{allowedCounterName: TftpBuildDialruleCount, prometheusName: "cucm_tftp_build_dial_rules", defaultEnabled: false},
+			{allowedCounterName: TftpBuildSoftKeyCount, prometheusName: "cucm_tftp_build_soft_keys", defaultEnabled: false},
+			{allowedCounterName: TftpBuildSignCount, prometheusName: "cucm_tftp_build_signed_files", defaultEnabled: false},
+			{allowedCounterName: HeartBeat, prometheusName: "cucm_tftp_heartbeat", defaultEnabled: false},
+		}},
+		// user login services
+		{groupName: "Cisco Extension Mobility", configName: "extensionMobility", instanceLabel: "instance", counters: []Counters{
+			{allowedCounterName: LoginsSuccessful, prometheusName: "cucm_em_logins_total", defaultEnabled: true, cumulative: true},
+			{allowedCounterName: LogoutsSuccessful, prometheusName: "cucm_em_logouts_total", defaultEnabled: true, cumulative: true},
+			{allowedCounterName: RequestsHandled, prometheusName: "cucm_em_requests_total", defaultEnabled: true, cumulative: true},
+			{allowedCounterName: RequestsFailed, prometheusName: "cucm_em_requests_failed_total", defaultEnabled: true, cumulative: true},
+			{allowedCounterName: RequestsThrottled, prometheusName: "cucm_em_requests_throttled_total", defaultEnabled: true, cumulative: true},
+			{allowedCounterName: RequestsInProgress, prometheusName: "cucm_em_requests_in_progress", defaultEnabled: true},
+			{allowedCounterName: AverageServiceTime, prometheusName: "cucm_em_average_service_time_seconds", defaultEnabled: true, scale: ScaleMillis},
+		}},
+		{groupName: "Cisco User Data Services", configName: "userDataServices", instanceLabel: "instance", counters: []Counters{
+			{allowedCounterName: Requests, prometheusName: "cucm_uds_requests_total", defaultEnabled: true, cumulative: true},
+			{allowedCounterName: RequestsFailed, prometheusName: "cucm_uds_requests_failed_total", defaultEnabled: true, cumulative: true},
+			{allowedCounterName: RequestsInProgress, prometheusName: "cucm_uds_requests_in_progress", defaultEnabled: true},
+			{allowedCounterName: AverageServiceTime, prometheusName: "cucm_uds_average_service_time_seconds", defaultEnabled: true, scale: ScaleMillis},
+		}},
+		// Cisco Unity Connection
+		{groupName: "CUC Sessions: Voice", configName: "cucSessions", instanceLabel: "instance", counters: []Counters{
+			{allowedCounterName: SessionsCurrent, prometheusName: "cuc_voice_sessions", defaultEnabled: true},
+			{allowedCounterName: SessionsTotal, prometheusName: "cuc_voice_sessions_total", defaultEnabled: true, cumulative: true},
+			{allowedCounterName: DelaySubscriberLogon, prometheusName: "cuc_voice_subscriber_logon_delay_seconds", defaultEnabled: false, scale: ScaleMillis},
+		}},
+		{groupName: "CUC Message Storage", configName: "cucMessageStore", instanceLabel: "instance", counters: []Counters{
+			{allowedCounterName: MessagesDeliveredTotal, prometheusName: "cuc_message_store_messages_delivered_total", defaultEnabled: true, cumulative: true},
+			{allowedCounterName: QueuedMessagesCurrent, prometheusName: "cuc_message_store_queued_messages", defaultEnabled: true},
+			{allowedCounterName: MessageSizeAverage, prometheusName: "cuc_message_store_message_size_average_bytes", defaultEnabled: false, scale: ScaleKilobytes},
+		}},
+		{groupName: "CUC Phone System", configName: "cucPorts", instanceLabel: "instance", counters: []Counters{
+			{allowedCounterName: PortsInUseCurrent, prometheusName: "cuc_ports_in_use", defaultEnabled: true},
+			{allowedCounterName: PortsIdleCurrent, prometheusName: "cuc_ports_idle", defaultEnabled: true},
+			{allowedCounterName: PortsLocked, prometheusName: "cuc_ports_locked", defaultEnabled: true},
+			{allowedCounterName: CallCountTotal, prometheusName: "cuc_calls_total", defaultEnabled: true, cumulative: true},
+		}},
+		// Cisco IM and Presence
+		{groupName: "Cisco XCP JSM", configName: "impXcpSessions", instanceLabel: "instance", counters: []Counters{
+			{allowedCounterName: JsmSessions, prometheusName: "imp_xcp_jsm_sessions", defaultEnabled: true},
+			{allowedCounterName: JsmIMSessions, prometheusName: "imp_xcp_jsm_im_sessions", defaultEnabled: true},
+		}},
+		{groupName: "Cisco Presence Engine", configName: "impPresence", instanceLabel: "instance", counters: []Counters{
+			{allowedCounterName: ActiveSubscriptions, prometheusName: "imp_presence_active_subscriptions", defaultEnabled: true},
+			{allowedCounterName: SubscribesReceived, prometheusName: "imp_presence_subscribes_received_total", defaultEnabled: true, cumulative: true},
+		}},
+		{groupName: "Cisco SIP Proxy", configName: "impSipProxy", instanceLabel: "instance", counters: []Counters{
+			{allowedCounterName: NumSipdWorker, prometheusName: "imp_sip_proxy_workers", defaultEnabled: true},
+			{allowedCounterName: NumIdleSipdWorkers, prometheusName: "imp_sip_proxy_idle_workers", defaultEnabled: true},
+			{allowedCounterName: SIPRetransmits, prometheusName: "imp_sip_proxy_retransmits_total", defaultEnabled: true, cumulative: true},
+		}},
+	}
+)
+
+// resourceCounters create common media device counters ResourceActive, ResourceAvailable, ResourceTotal and OutOfResources
+func resourceCounters(prefix string) []Counters {
+	return []Counters{
+		{allowedCounterName: ResourceActive, prometheusName: prefix + "_resource_active", defaultEnabled: true},
+		{allowedCounterName: ResourceAvailable, prometheusName: prefix + "_resource_available", defaultEnabled: true},
+		{allowedCounterName: ResourceTotal, prometheusName: prefix + "_resource_total", defaultEnabled: true},
+		{allowedCounterName: OutOfResources, prometheusName: prefix + "_out_of_resources_total", defaultEnabled: true, cumulative: true},
+	}
+}
+
+// channelCounters create status counters "Channel N Status" for channels 1 to channels with common Prometheus name
+func channelCounters(prometheusName string, channels int) []Counters {
+	c := make([]Counters, 0, channels)
+	for i := 1; i <= channels; i++ {
+		c = append(c, Counters{allowedCounterName: fmt.Sprintf("Channel %d Status", i), prometheusName: prometheusName, defaultEnabled: true, channel: i})
+	}
+	return c
+}
+
+// objectCounters join counters lists of object
+func objectCounters(lists ...[]Counters) []Counters {
+	c := make([]Counters, 0)
+	for _, l := range lists {
+		c = append(c, l...)
+	}
+	return c
+}
+
+// supportedObject find supported object definition by PerfMon object name
+func supportedObject(group string) *ObjectCounters {
+	for i := range SupportedObjects {
+		if SupportedObjects[i].groupName == group {
+			return &SupportedObjects[i]
+		}
+	}
+	return nil
+}
+
+// splitInstance split PerfMon group to object name and instance, i.e. "Cisco SIP(trunk)" -> "Cisco SIP", "trunk"
+func splitInstance(group string) (object string, instance string) {
+	start := strings.Index(group, "(")
+	if start < 0 || !strings.HasSuffix(group, ")") {
+		return group, ""
+	}
+	return group[:start], group[start+1 : len(group)-1]
+}
+
+// objectKey unique key of counter in object
+func objectKey(group string, counter string) string {
+	return fmt.Sprintf("%s\\%s", group, counter)
+}
+
+// enabled is object enabled in configuration
+func (o *ObjectCounters) enabled() bool {
+	if cfg, ok := config.Objects[o.configName]; ok {
+		return cfg.Enabled
+	}
+	profile := productProfile(config.Product)
+	return profile != nil && profile.objectDefault(o.configName)
+}
+
+// counter find supported counter of object by name
+func (o *ObjectCounters) counter(name string) *Counters {
+	for i := range o.counters {
+		if o.counters[i].allowedCounterName == name {
+			return &o.counters[i]
+		}
+	}
+	return nil
+}
+
+// metricName Prometheus metric name of counter, configuration overwrite default name
+func (o *ObjectCounters) metricName(c *Counters) string {
+	if cfg, ok := config.Objects[o.configName]; ok {
+		if name, ok := cfg.Names[c.allowedCounterName]; ok {
+			return name
+		}
+	}
+	return c.prometheusName
+}
+
+// counterEnabled is counter of object exported, configuration overwrite counter default
+func (o *ObjectCounters) counterEnabled(name string) bool {
+	if !o.enabled() {
+		return false
+	}
+	c := o.counter(name)
+	if c == nil {
+		return false
+	}
+	if cfg, ok := config.Objects[o.configName]; ok {
+		if enabled, ok := cfg.Counters[name]; ok {
+			return enabled
+		}
+	}
+	return c.defaultEnabled
+}
+
+// counterCollected is counter of object added to session, exported or required for computed metrics
+func (o *ObjectCounters) counterCollected(name string) bool {
+	if o.counterEnabled(name) {
+		return true
+	}
+	c := o.counter(name)
+	return c != nil && c.required && o.enabled()
+}
+
+// instanceAllowed is instance of multi-instance object allowed by include and exclude rules
+//   - object without configuration (i.e. enabled by product profile) hasn't rules, all instances are allowed
+func (o *ObjectCounters) instanceAllowed(instance string) bool {
+	cfg, ok := config.Objects[o.configName]
+	if !ok {
+		return true
+	}
+	return cfg.instanceAllowed(instance)
+}
@@ -0,0 +1,38 @@
+package main
+
+import "testing"
+
+func TestStandardGroup(t *testing.T) {
+	saved := config.Objects
+	defer func() { config.Objects = saved }()
+
+	config.Objects = map[string]*ConfigObject{"recording": {Enabled: false}}
+	if !standardGroup("Cisco Recording") || !standardGroup("Cisco CallManager") {
+		t.Errorf("allowed group isn't standard when object is disabled")
+	}
+	config.Objects = map[string]*ConfigObject{"recording": {Enabled: true}}
+	if standardGroup("Cisco Recording") {
+		t.Errorf("enabled object Cisco Recording is exported as standard counters too")
+	}
+	if !standardGroup("Cisco CallManager") || standardGroup("Memory") {
+		t.Errorf("standard groups changed by enabled recording object")
+	}
+}
+
+func TestSipTrunkRequiredCounters(t *testing.T) {
+	saved := config.Objects
+	defer func() { config.Objects = saved }()
+
+	config.Objects = map[string]*ConfigObject{"sip": {Enabled: true, Counters: map[string]bool{
+		CallsAttempted: false, CallsCompleted: false, CallsInProgress: false, VideoCallsActive: false,
+	}}}
+	object := supportedObject(SipTrunkGroup)
+	for _, name := range []string{CallsAttempted, CallsCompleted, CallsInProgress} {
+		if object.counterEnabled(name) || !object.counterCollected(name) {
+			t.Errorf("disabled counter %s isn't collected for failed calls", name)
+		}
+	}
+	if object.counterCollected(VideoCallsActive) {
+		t.Errorf("disabled not required counter %s is collected", VideoCallsActive)
+	}
+}
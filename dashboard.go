@@ -0,0 +1,104 @@
+package main
+
+import (
+	"html/template"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// DashboardRefresh seconds between automatic refresh of dashboard page
+const DashboardRefresh = 10
+
+// ExportedValue current value of exported counter
+type ExportedValue struct {
+	CounterValue
+	PrometheusName string `json:"prometheusName"`
+}
+
+// dashboardPage data for HTML dashboard
+type dashboardPage struct {
+	Title     string
+	Version   string
+	Refresh   int
+	AllowStop bool
+	Status    StatusSnapshot
+	Values    []ExportedValue
+}
+
+// exportedValues current values of all exported counters
+func exportedValues() []ExportedValue {
+	values := make([]ExportedValue, 0)
+	for _, v := range counterValues.list() {
+		enabled, prometheusName := counterExport(v.Object, v.Counter)
+		if !enabled {
+			continue
+		}
+		values = append(values, ExportedValue{CounterValue: v, PrometheusName: prometheusName})
+	}
+	return values
+}
+
+// dashboardHandler HTTP handler for / and /status, HTML dashboard or JSON status
+func dashboardHandler(w http.ResponseWriter, r *http.Request) {
+	log.WithFields(log.Fields{"metricsUri": r.URL.Path, FieldRoutine: "dashboardHandler"}).Debugf("request %s", r.URL.Path)
+	if r.URL.Path != "/" && r.URL.Path != "/status" {
+		http.NotFound(w, r)
+		return
+	}
+	status := monitorStatus.snapshot()
+	if wantJson(r) {
+		writeJson(w, http.StatusOK, status)
+		return
+	}
+	page := dashboardPage{
+		Title:     applicationName,
+		Version:   Version,
+		Refresh:   DashboardRefresh,
+		AllowStop: config.AllowStop,
+		Status:    status,
+		Values:    exportedValues(),
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := dashboardTemplate.Execute(w, page); err != nil {
+		log.WithFields(log.Fields{FieldRoutine: "dashboardHandler"}).Errorf("problem render dashboard. Error: %s", err)
+	}
+}
+
+var dashboardTemplate = template.Must(template.New("dashboard").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><meta http-equiv="refresh" content="{{.Refresh}}"><title>{{.Title}}</title>
+<style>
+body{font-family:sans-serif;font-size:13px}table{border-collapse:collapse;margin-bottom:12px}td,th{border:1px solid #ccc;padding:2px 6px;vertical-align:top}
+th{background:#eee;text-align:left}.ok{color:#080;font-weight:bold}.bad{color:#c00;font-weight:bold}nav a{margin-right:12px}
+</style></head><body>
+<h2>{{.Title}} <small>{{.Version}}</small></h2>
+<nav><a href="/metrics">Metrics</a><a href="/counters">Counters</a><a href="/config">Configuration</a><a href="/version">Version</a><a href="/status?format=json">Status JSON</a>{{if .AllowStop}}<a href="/stop">Stop program</a>{{end}}</nav>
+<p>Page refresh every {{.Refresh}}s</p>
+{{with .Status}}
+<h3>Session</h3>
+<table>
+<tr><th>Connected</th><td>{{if .Connected}}<span class="ok">yes</span>{{else}}<span class="bad">no</span>{{end}}</td></tr>
+<tr><th>Session age</th><td>{{if .Connected}}{{.SessionAge}}{{else}}-{{end}}</td></tr>
+<tr><th>Last collection</th><td>{{if .LastCollection.IsZero}}-{{else}}{{.LastCollection.Format "2006-01-02 15:04:05"}}{{end}}</td></tr>
+<tr><th>Last collection duration</th><td>{{.LastDuration}}</td></tr>
+<tr><th>Collections</th><td>{{.Collections}}</td></tr>
+<tr><th>API requests / responses / errors</th><td>{{.Requests}} / {{.Responses}} / {{.ResponseErrors}}</td></tr>
+<tr><th>Rate limiter</th><td>{{.Rate.Requests}} of {{.Rate.Limit}} requests per minute{{if not .Rate.WindowStart.IsZero}} since {{.Rate.WindowStart.Format "15:04:05"}}{{end}}</td></tr>
+</table>
+<h3>Nodes</h3>
+<table><tr><th>Server</th><th>Connected</th><th>Last seen</th><th>Valid values</th><th>Invalid values</th></tr>
+{{range .Nodes}}<tr><td>{{.Server}}</td><td>{{if .Connected}}<span class="ok">yes</span>{{else}}<span class="bad">no</span>{{end}}</td>
+<td>{{if .LastSeen.IsZero}}-{{else}}{{.LastSeen.Format "2006-01-02 15:04:05"}}{{end}}</td><td>{{.Values}}</td><td>{{.InvalidValues}}</td></tr>
+{{end}}</table>
+<h3>Recent errors</h3>
+{{if .Errors}}<table><tr><th>Time</th><th>Operation</th><th>Type</th><th>Message</th></tr>
+{{range .Errors}}<tr><td>{{.Time.Format "2006-01-02 15:04:05"}}</td><td>{{.Operation}}</td><td>{{.Kind}}</td><td>{{.Message}}</td></tr>
+{{end}}</table>{{else}}<p>No errors</p>{{end}}
+{{end}}
+<h3>Exported counters</h3>
+<table><tr><th>Server</th><th>Object</th><th>Instance</th><th>Counter</th><th>Prometheus name</th><th>Value</th><th>Status</th><th>Time</th></tr>
+{{range .Values}}<tr><td>{{.Server}}</td><td>{{.Object}}</td><td>{{.Instance}}</td><td>{{.Counter}}</td><td>{{.PrometheusName}}</td><td>{{.Value}}</td>
+<td>{{if .Valid}}<span class="ok">{{.CStatus}}</span>{{else}}<span class="bad">{{.CStatus}}</span>{{end}}</td><td>{{if not .Time.IsZero}}{{.Time.Format "15:04:05"}}{{end}}</td></tr>
+{{end}}</table>
+</body></html>
+`))
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportedValues(t *testing.T) {
+	savedCallsActive, savedObjects := config.Metrics.CallsActive, config.Objects
+	defer func() {
+		config.Metrics.CallsActive, config.Objects = savedCallsActive, savedObjects
+		counterValues.reset()
+	}()
+	config.Metrics.CallsActive = true
+	counterValues.reset()
+	config.Objects = map[string]*ConfigObject{}
+	now := time.Now()
+	counterValues.observe("node1", BusyHourGroup, CallsActive, &OneCollectData{Value: 4}, now)
+	counterValues.observe("node1", SipTrunkGroup+"(trunk01)", CallsActive, &OneCollectData{Value: 2}, now)
+	counterValues.observe("node1", "Cisco Unknown", "Unknown", &OneCollectData{Value: 1}, now)
+
+	values := exportedValues()
+	if len(values) != 1 || values[0].PrometheusName != "cucm_calls_active" || values[0].Value != 4 {
+		t.Errorf("exported values %v, want only cucm_calls_active with value 4", values)
+	}
+}
+
+func TestDashboardHandler(t *testing.T) {
+	savedStatus, savedNames := monitorStatus, config.MonitorNames
+	defer func() { monitorStatus, config.MonitorNames = savedStatus, savedNames }()
+	monitorStatus = NewMonitorStatus()
+	config.MonitorNames = []string{"node1", "node2"}
+	start := time.Now()
+	monitorStatus.collected(start, &SessionData{CollectData: []OneCollectData{
+		{Name: "\\\\node1\\Cisco CallManager\\CallsActive", Value: 1, CStatus: "1"},
+		{Name: "\\\\node1\\Cisco CallManager\\CallsAttempted", Value: 1, CStatus: "3"},
+	}})
+	monitorStatus.addError("CollectSessionData", string(ErrTimeout), "request timeout")
+
+	w := httptest.NewRecorder()
+	dashboardHandler(w, httptest.NewRequest(http.MethodGet, "/status?format=json", nil))
+	var status StatusSnapshot
+	if err := json.NewDecoder(w.Body).Decode(&status); err != nil {
+		t.Fatalf("decode JSON status error %v", err)
+	}
+	if status.Collections != 1 || len(status.Nodes) != 2 || len(status.Errors) != 1 {
+		t.Fatalf("status has %d collections, %d nodes, %d errors, want 1, 2, 1", status.Collections, len(status.Nodes), len(status.Errors))
+	}
+	if node := status.Nodes[0]; !node.Connected || node.Values != 1 || node.InvalidValues != 1 {
+		t.Errorf("node1 status %+v, want connected with one valid and one invalid value", node)
+	}
+	if node := status.Nodes[1]; node.Server != "node2" || node.Connected {
+		t.Errorf("node2 status %+v, want not connected node2", node)
+	}
+
+	w = httptest.NewRecorder()
+	dashboardHandler(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if body := w.Body.String(); !strings.Contains(body, "request timeout") || !strings.Contains(body, "<td>node2</td>") {
+		t.Errorf("HTML dashboard doesn't contain error and all nodes")
+	}
+
+	w = httptest.NewRecorder()
+	dashboardHandler(w, httptest.NewRequest(http.MethodGet, "/unknown", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unknown path status %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// GaugeSampling collect gauge samples and export min/max/avg of samples in rolling window
+//   - window is independent on scrapes, every scraper (i.e. more Prometheus replicas) get same values
+type GaugeSampling struct {
+	mutex    sync.Mutex
+	window   time.Duration              // window length of rolling window
+	now      func() time.Time           // now actual time, replaced in tests
+	counters map[string]*sampledCounter // counters sampled gauges by counter name
+}
+
+// sampledCounter descriptions and samples for one gauge
+type sampledCounter struct {
+	min    *prometheus.Desc
+	max    *prometheus.Desc
+	avg    *prometheus.Desc
+	labels []string                // labels names of gauge
+	stats  map[string]*sampleStats // stats by joined label values
+}
+
+// timedSample one sample with collection time
+type timedSample struct {
+	time  time.Time
+	value float64
+}
+
+// sampleStats samples of one gauge series in rolling window
+type sampleStats struct {
+	labelValues []string      // labelValues values of labels for series
+	samples     []timedSample // samples in window sorted by time
+}
+
+// NewGaugeSampling create empty gauge sampling collector with rolling window
+func NewGaugeSampling(window time.Duration) *GaugeSampling {
+	return &GaugeSampling{window: window, now: time.Now, counters: make(map[string]*sampledCounter)}
+}
+
+// addCounter register gauge for sampling, Prometheus names are created from gauge name with suffix _min, _max and _avg
+func (g *GaugeSampling) addCounter(counter string, prometheusName string, help string, labels []string) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	g.counters[counter] = &sampledCounter{
+		min:    prometheus.NewDesc(prometheusName+"_min", fmt.Sprintf("Minimum in last %s. %s", g.window, help), labels, nil),
+		max:    prometheus.NewDesc(prometheusName+"_max", fmt.Sprintf("Maximum in last %s. %s", g.window, help), labels, nil),
+		avg:    prometheus.NewDesc(prometheusName+"_avg", fmt.Sprintf("Average in last %s. %s", g.window, help), labels, nil),
+		labels: labels,
+		stats:  make(map[string]*sampleStats),
+	}
+}
+
+// observe add one sample for gauge series
+func (g *GaugeSampling) observe(counter string, value float64, labelValues ...string) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	c, ok := g.counters[counter]
+	if !ok {
+		return
+	}
+	key := strings.Join(labelValues, "\x00")
+	s, ok := c.stats[key]
+	if !ok {
+		s = &sampleStats{labelValues: labelValues}
+		c.stats[key] = s
+	}
+	now := g.now()
+	s.prune(now.Add(-g.window))
+	s.samples = append(s.samples, timedSample{time: now, value: value})
+}
+
+// remove forget series of gauge, i.e. series of instance removed from session
+func (g *GaugeSampling) remove(counter string, labelValues ...string) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	if c, ok := g.counters[counter]; ok {
+		delete(c.stats, strings.Join(labelValues, "\x00"))
+	}
+}
+
+// prune remove samples older than limit
+func (s *sampleStats) prune(limit time.Time) {
+	i := 0
+	for i < len(s.samples) && s.samples[i].time.Before(limit) {
+		i++
+	}
+	if i > 0 {
+		s.samples = append(s.samples[:0], s.samples[i:]...)
+	}
+}
+
+// aggregate min, max and avg of samples in window, window must contain at least one sample
+func (s *sampleStats) aggregate() (minValue float64, maxValue float64, avgValue float64) {
+	minValue, maxValue = s.samples[0].value, s.samples[0].value
+	sum := 0.0
+	for _, sample := range s.samples {
+		if sample.value < minValue {
+			minValue = sample.value
+		}
+		if sample.value > maxValue {
+			maxValue = sample.value
+		}
+		sum += sample.value
+	}
+	return minValue, maxValue, sum / float64(len(s.samples))
+}
+
+// Describe implements prometheus.Collector
+func (g *GaugeSampling) Describe(ch chan<- *prometheus.Desc) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	for _, c := range g.counters {
+		ch <- c.min
+		ch <- c.max
+		ch <- c.avg
+	}
+}
+
+// Collect implements prometheus.Collector, scrape doesn't change samples in window
+//   - series without sample in window (stopped reporting) is deleted
+func (g *GaugeSampling) Collect(ch chan<- prometheus.Metric) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	limit := g.now().Add(-g.window)
+	for _, c := range g.counters {
+		for key, s := range c.stats {
+			s.prune(limit)
+			if len(s.samples) == 0 {
+				delete(c.stats, key)
+				continue
+			}
+			minValue, maxValue, avgValue := s.aggregate()
+			ch <- prometheus.MustNewConstMetric(c.min, prometheus.GaugeValue, minValue, s.labelValues...)
+			ch <- prometheus.MustNewConstMetric(c.max, prometheus.GaugeValue, maxValue, s.labelValues...)
+			ch <- prometheus.MustNewConstMetric(c.avg, prometheus.GaugeValue, avgValue, s.labelValues...)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// collectSampling collect min, max and avg values of one series
+func collectSampling(t *testing.T, g *GaugeSampling) []float64 {
+	t.Helper()
+	values := make([]float64, 0, 3)
+	for _, m := range collectMetrics(t, g) {
+		values = append(values, metricValue(m))
+	}
+	return values
+}
+
+func TestGaugeSamplingRollingWindow(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	g := NewGaugeSampling(time.Minute)
+	g.now = func() time.Time { return now }
+	g.addCounter("CallsActive", "cucm_calls_active", "help", []string{"server"})
+
+	for _, v := range []float64{10, 20, 30} {
+		g.observe("CallsActive", v, "node1")
+		now = now.Add(20 * time.Second)
+	}
+	// more scrapes in same time must return same values
+	for scrape := 0; scrape < 3; scrape++ {
+		if got := collectSampling(t, g); got[0] != 10 || got[1] != 30 || got[2] != 20 {
+			t.Fatalf("scrape %d got min/max/avg %v, want [10 30 20]", scrape, got)
+		}
+	}
+	// first sample is out of window
+	now = now.Add(5 * time.Second)
+	if got := collectSampling(t, g); got[0] != 20 || got[1] != 30 || got[2] != 25 {
+		t.Errorf("got min/max/avg %v, want [20 30 25]", got)
+	}
+	// series with empty window is deleted
+	now = now.Add(time.Hour)
+	if got := collectSampling(t, g); len(got) != 0 {
+		t.Errorf("got min/max/avg %v for empty window, want deleted series", got)
+	}
+	if len(g.counters["CallsActive"].stats) != 0 {
+		t.Errorf("series with empty window isn't deleted")
+	}
+}
+
+func TestGaugeSamplingRemove(t *testing.T) {
+	g := NewGaugeSampling(time.Minute)
+	g.addCounter("Cisco SIP\\CallsActive", "cucm_sip_trunk_calls_active", "help", []string{"server", "trunk"})
+	g.observe("Cisco SIP\\CallsActive", 1, "node1", "trunk01")
+	g.observe("Cisco SIP\\CallsActive", 2, "node1", "trunk02")
+	g.remove("Cisco SIP\\CallsActive", "node1", "trunk01")
+	if got := collectSampling(t, g); len(got) != 3 || got[0] != 2 {
+		t.Errorf("got %v after remove of trunk01, want only trunk02 values", got)
+	}
+}
+
+func TestValidateRateBudget(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval int
+		nodes    int
+		wantErr  bool
+	}{
+		{"small cluster fast sampling", 2, 3, false},
+		{"large cluster fast sampling", 2, 12, true},
+		{"large cluster slow sampling", 5, 12, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Config{SleepBetweenRequest: 30, Sampling: ConfigSampling{Enabled: true, Interval: tt.interval}}
+			c.MonitorNames = make([]string, tt.nodes)
+			if err := c.validateRateBudget(); (err != nil) != tt.wantErr {
+				t.Errorf("validateRateBudget() error %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
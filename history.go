@@ -0,0 +1,247 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	HistoryDefaultDirectory = "history"   // HistoryDefaultDirectory default directory for history segments
+	HistorySegmentDuration  = time.Hour   // HistorySegmentDuration maximal time range of one segment file
+	HistoryFilePrefix       = "history-"  // HistoryFilePrefix prefix of segment file, followed by segment start in unix seconds
+	HistoryFileSuffix       = ".tsv.gz"   // HistoryFileSuffix suffix of segment file
+	HistoryFlushInterval    = time.Minute // HistoryFlushInterval minimal time between flush of actual segment to disk
+)
+
+// History on-disk ring buffer of collected samples
+//   - samples are stored in gzip compressed segment files, new segment starts every hour and after program start
+//   - segments older than retention are removed
+//   - one line of segment contains time in unix milliseconds, server, object, instance, counter and value separated by tab
+//   - actual segment is flushed every HistoryFlushInterval, every flush ends gzip block and decrease compression
+type History struct {
+	mutex        sync.Mutex
+	directory    string
+	retention    time.Duration
+	file         *os.File
+	buffer       *bufio.Writer
+	writer       *gzip.Writer
+	segmentStart time.Time
+	flushedAt    time.Time // flushedAt time of last flush of actual segment
+}
+
+// historySegment one segment file
+type historySegment struct {
+	fileName string
+	start    time.Time
+}
+
+// history local history of samples, nil when history is disabled
+var history *History
+
+// NewHistory create history in directory, directory is created when not exists
+func NewHistory(directory string, retentionDays int) (*History, error) {
+	if err := os.MkdirAll(directory, 0o750); err != nil {
+		return nil, err
+	}
+	h := &History{directory: directory, retention: time.Duration(retentionDays) * 24 * time.Hour}
+	h.cleanup(time.Now())
+	return h, nil
+}
+
+// write store all valid samples of collected data
+func (h *History) write(now time.Time, data *SessionData) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if err := h.rotate(now); err != nil {
+		log.WithFields(log.Fields{FieldRoutine: "historyWrite"}).Errorf("problem open history segment. Error: %s", err)
+		return
+	}
+	for i := range data.CollectData {
+		if !data.CollectData[i].valid() {
+			continue
+		}
+		server, group, counter, err := data.CollectData[i].splitName()
+		if err != nil {
+			continue
+		}
+		object, instance := splitInstance(group)
+		_, _ = fmt.Fprintf(h.writer, "%d\t%s\t%s\t%s\t%s\t%s\n", now.UnixMilli(), historyField(server), historyField(object),
+			historyField(instance), historyField(counter), strconv.FormatFloat(data.CollectData[i].Value, 'g', -1, 64))
+	}
+	if now.Sub(h.flushedAt) < HistoryFlushInterval {
+		return
+	}
+	// flush makes samples readable by scan and keeps them on disk after crash
+	h.flushedAt = now
+	err := h.writer.Flush()
+	if err == nil {
+		err = h.buffer.Flush()
+	}
+	if err != nil {
+		log.WithFields(log.Fields{FieldRoutine: "historyWrite"}).Errorf("problem write history segment %s. Error: %s", h.file.Name(), err)
+	}
+}
+
+// rotate open new segment when no segment is open or actual segment is older than segment duration
+func (h *History) rotate(now time.Time) error {
+	if h.file != nil && now.Sub(h.segmentStart) < HistorySegmentDuration {
+		return nil
+	}
+	h.closeSegment()
+	h.cleanup(now)
+	fileName := filepath.Join(h.directory, fmt.Sprintf("%s%d%s", HistoryFilePrefix, now.Unix(), HistoryFileSuffix))
+	file, err := os.OpenFile(fileName, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	log.WithFields(log.Fields{FieldRoutine: "historyRotate"}).Debugf("open history segment %s", fileName)
+	h.file = file
+	h.buffer = bufio.NewWriter(file)
+	h.writer = gzip.NewWriter(h.buffer)
+	h.segmentStart = now
+	h.flushedAt = now
+	return nil
+}
+
+// closeSegment close actual segment file
+func (h *History) closeSegment() {
+	if h.file == nil {
+		return
+	}
+	err := h.writer.Close()
+	if err == nil {
+		err = h.buffer.Flush()
+	}
+	if e := h.file.Close(); err == nil {
+		err = e
+	}
+	if err != nil {
+		log.WithFields(log.Fields{FieldRoutine: "historyClose"}).Errorf("problem close history segment %s. Error: %s", h.file.Name(), err)
+	}
+	h.file, h.buffer, h.writer = nil, nil, nil
+}
+
+// close actual segment, used when program ends
+func (h *History) close() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.closeSegment()
+}
+
+// cleanup remove segments with all samples older than retention
+func (h *History) cleanup(now time.Time) {
+	limit := now.Add(-h.retention - HistorySegmentDuration)
+	for _, segment := range h.segments() {
+		if !segment.start.Before(limit) {
+			continue
+		}
+		if err := os.Remove(segment.fileName); err != nil {
+			log.WithFields(log.Fields{FieldRoutine: "historyCleanup"}).Errorf("problem remove history segment %s. Error: %s", segment.fileName, err)
+		} else {
+			log.WithFields(log.Fields{FieldRoutine: "historyCleanup"}).Debugf("removed history segment %s", segment.fileName)
+		}
+	}
+}
+
+// segments all segment files sorted by start
+func (h *History) segments() []historySegment {
+	entries, err := os.ReadDir(h.directory)
+	if err != nil {
+		log.WithFields(log.Fields{FieldRoutine: "historySegments"}).Errorf("problem read history directory %s. Error: %s", h.directory, err)
+		return nil
+	}
+	segments := make([]historySegment, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, HistoryFilePrefix) || !strings.HasSuffix(name, HistoryFileSuffix) {
+			continue
+		}
+		start, err := strconv.ParseInt(strings.TrimSuffix(strings.TrimPrefix(name, HistoryFilePrefix), HistoryFileSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		segments = append(segments, historySegment{fileName: filepath.Join(h.directory, name), start: time.Unix(start, 0)})
+	}
+	sort.Slice(segments, func(i, j int) bool { return segments[i].start.Before(segments[j].start) })
+	return segments
+}
+
+// scan call function for every sample in time range [from, to] matching filter, samples are sorted by time
+func (h *History) scan(from time.Time, to time.Time, filter *ValuesFilter, fn func(v *CounterValue) error) error {
+	for _, segment := range h.segments() {
+		if segment.start.After(to) || segment.start.Add(HistorySegmentDuration).Before(from) {
+			continue
+		}
+		if err := scanSegment(segment.fileName, from, to, filter, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scanSegment read samples of one segment, truncated segment (actual or after crash) is read to last complete line
+func scanSegment(fileName string, from time.Time, to time.Time, filter *ValuesFilter, fn func(v *CounterValue) error) error {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+	reader, err := gzip.NewReader(bufio.NewReader(file))
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil // empty segment
+		}
+		log.WithFields(log.Fields{FieldRoutine: "historyScan"}).Errorf("problem read history segment %s. Error: %s", fileName, err)
+		return nil
+	}
+	defer func() { _ = reader.Close() }()
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		v, ok := parseHistoryLine(scanner.Text())
+		if !ok || v.Time.Before(from) || v.Time.After(to) || !filter.match(v) {
+			continue
+		}
+		if err = fn(v); err != nil {
+			return err
+		}
+	}
+	if err = scanner.Err(); err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		log.WithFields(log.Fields{FieldRoutine: "historyScan"}).Errorf("problem read history segment %s. Error: %s", fileName, err)
+	}
+	return nil
+}
+
+// parseHistoryLine parse one line of segment
+func parseHistoryLine(line string) (*CounterValue, bool) {
+	fields := strings.Split(line, "\t")
+	if len(fields) != 6 {
+		return nil, false
+	}
+	ms, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return nil, false
+	}
+	value, err := strconv.ParseFloat(fields[5], 64)
+	if err != nil {
+		return nil, false
+	}
+	return &CounterValue{Time: time.UnixMilli(ms), Server: fields[1], Object: fields[2], Instance: fields[3], Counter: fields[4],
+		Value: value, Valid: true}, true
+}
+
+// historyField replace characters used as separators in segment line
+func historyField(value string) string {
+	return strings.NewReplacer("\t", " ", "\n", " ", "\r", " ").Replace(value)
+}
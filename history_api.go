@@ -0,0 +1,290 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	HistoryQueryDefaultRange = time.Hour            // HistoryQueryDefaultRange range of query when start isn't defined
+	HistoryQueryMaxSamples   = 500000               // HistoryQueryMaxSamples maximal number of samples returned by range query
+	HistoryExportMetric      = "cucm_perfmon_value" // HistoryExportMetric metric name in OpenMetrics export for counters not exported as metric
+)
+
+// HistorySeries samples of one counter, sample is pair of unix time in seconds and value
+type HistorySeries struct {
+	Server   string       `json:"server"`
+	Object   string       `json:"object"`
+	Instance string       `json:"instance,omitempty"`
+	Counter  string       `json:"counter"`
+	Samples  [][2]float64 `json:"samples"`
+}
+
+// HistoryResult response of history range query
+type HistoryResult struct {
+	Start  time.Time       `json:"start"`
+	End    time.Time       `json:"end"`
+	Count  int             `json:"count"`
+	Series []HistorySeries `json:"series"`
+}
+
+// errHistoryLimit range query return too many samples
+var errHistoryLimit = fmt.Errorf("query return more than %d samples, use shorter range or filter", HistoryQueryMaxSamples)
+
+// parseHistoryTime parse time in unix seconds or RFC3339 format, empty value return default
+func parseHistoryTime(value string, defaultTime time.Time) (time.Time, error) {
+	if len(value) == 0 {
+		return defaultTime, nil
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.UnixMilli(int64(seconds * 1000)), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// historyRange read time range from parameters start and end, default end is now
+func historyRange(r *http.Request, defaultRange time.Duration) (from time.Time, to time.Time, err error) {
+	if to, err = parseHistoryTime(r.URL.Query().Get("end"), time.Now()); err != nil {
+		return from, to, fmt.Errorf("invalid end: %w", err)
+	}
+	if from, err = parseHistoryTime(r.URL.Query().Get("start"), to.Add(-defaultRange)); err != nil {
+		return from, to, fmt.Errorf("invalid start: %w", err)
+	}
+	if from.After(to) {
+		return from, to, errors.New("start is after end")
+	}
+	return from, to, nil
+}
+
+// historyRequest read time range and filter of history request, write error response when request isn't valid
+func historyRequest(w http.ResponseWriter, r *http.Request, defaultRange time.Duration) (from time.Time, to time.Time, filter *ValuesFilter, ok bool) {
+	if history == nil {
+		writeJson(w, http.StatusNotFound, ApiErrorResponse{Error: "history is not enabled"})
+		return from, to, nil, false
+	}
+	var err error
+	if from, to, err = historyRange(r, defaultRange); err != nil {
+		writeJson(w, http.StatusBadRequest, ApiErrorResponse{Error: err.Error()})
+		return from, to, nil, false
+	}
+	return from, to, newValuesFilter(r), true
+}
+
+// apiHistoryHandler HTTP handler for /api/v1/history, samples in time range grouped by counter
+func apiHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	log.WithFields(log.Fields{"metricsUri": r.URL.Path, FieldRoutine: "apiHistoryHandler"}).Debugf("request %s", r.URL.Path)
+	from, to, filter, ok := historyRequest(w, r, HistoryQueryDefaultRange)
+	if !ok {
+		return
+	}
+	result := HistoryResult{Start: from, End: to, Series: make([]HistorySeries, 0)}
+	index := make(map[string]int)
+	err := history.scan(from, to, filter, func(v *CounterValue) error {
+		if result.Count >= HistoryQueryMaxSamples {
+			return errHistoryLimit
+		}
+		key := objectKey(v.Server, objectKey(v.Object, objectKey(v.Instance, v.Counter)))
+		i, ok := index[key]
+		if !ok {
+			i = len(result.Series)
+			index[key] = i
+			result.Series = append(result.Series, HistorySeries{Server: v.Server, Object: v.Object, Instance: v.Instance, Counter: v.Counter})
+		}
+		result.Series[i].Samples = append(result.Series[i].Samples, [2]float64{float64(v.Time.UnixMilli()) / 1000, v.Value})
+		result.Count++
+		return nil
+	})
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, errHistoryLimit) {
+			status = http.StatusBadRequest
+		}
+		writeJson(w, status, ApiErrorResponse{Error: err.Error()})
+		return
+	}
+	sort.Slice(result.Series, func(i, j int) bool {
+		a, b := result.Series[i], result.Series[j]
+		return objectKey(a.Server, objectKey(a.Object, objectKey(a.Instance, a.Counter))) <
+			objectKey(b.Server, objectKey(b.Object, objectKey(b.Instance, b.Counter)))
+	})
+	writeJson(w, http.StatusOK, result)
+}
+
+// historyMetric live Prometheus metric of history sample
+type historyMetric struct {
+	name          string   // name of metric
+	help          string   // help of metric, description of counter when known
+	counter       bool     // counter metric, gauge otherwise
+	instanceLabel string   // instanceLabel label of object instance, empty for metric without instance
+	scale         float64  // scale multiplier for conversion to base unit, 0 is without conversion
+	states        []string // states names of state set, exported with label status
+	unknown       int      // unknown index of state for value out of range
+}
+
+// historyExportFamily samples of one metric family stored in temporary file during export
+type historyExportFamily struct {
+	metric historyMetric
+	file   *os.File
+	buffer *bufio.Writer
+}
+
+// newHistoryMetric live metric of sample, counters not exported as metric use generic HistoryExportMetric
+//   - channel status counters are exported as generic metric, live metric is count of channels by status
+func newHistoryMetric(v *CounterValue) historyMetric {
+	help, _ := knownDescription(v.Object, v.Counter)
+	if standardGroup(v.Object) {
+		if c := supportedCounter(v.Counter); c != nil && config.Metrics.enablePrometheusCounter(v.Counter) {
+			return historyMetric{name: c.prometheusName, help: help, counter: strings.HasSuffix(strings.ToLower(v.Counter), "failed")}
+		}
+	}
+	if o := supportedObject(v.Object); o != nil && o.counterEnabled(v.Counter) {
+		c := o.counter(v.Counter)
+		if c.channel == 0 {
+			m := historyMetric{name: o.metricName(c), help: help, counter: c.cumulative, scale: c.scale}
+			if len(v.Instance) > 0 {
+				m.instanceLabel = o.instanceLabel
+			}
+			if len(c.states) > 0 {
+				m.states, m.unknown = statusNames(c.states)
+			}
+			return m
+		}
+	}
+	return historyMetric{name: HistoryExportMetric, help: "PerfMon counter value from local history"}
+}
+
+// family name of metric family and type in OpenMetrics, counter without suffix _total has type unknown
+func (m *historyMetric) family() (name string, metricType string) {
+	if m.counter && strings.HasSuffix(m.name, "_total") {
+		return strings.TrimSuffix(m.name, "_total"), "counter"
+	}
+	if m.counter {
+		return m.name, "unknown"
+	}
+	return m.name, "gauge"
+}
+
+// write sample in OpenMetrics text format, state set write one line per state
+func (m *historyMetric) write(out io.Writer, v *CounterValue) error {
+	labels := fmt.Sprintf("server=\"%s\"", openMetricsLabel(v.Server))
+	if m.name == HistoryExportMetric {
+		labels = fmt.Sprintf("%s,object=\"%s\",instance=\"%s\",counter=\"%s\"", labels,
+			openMetricsLabel(v.Object), openMetricsLabel(v.Instance), openMetricsLabel(v.Counter))
+	} else if len(m.instanceLabel) > 0 {
+		labels = fmt.Sprintf("%s,%s=\"%s\"", labels, m.instanceLabel, openMetricsLabel(v.Instance))
+	}
+	timestamp := strconv.FormatFloat(float64(v.Time.UnixMilli())/1000, 'f', 3, 64)
+	if len(m.states) > 0 {
+		actual := statusName(m.states, m.unknown, v.Value)
+		for _, state := range m.states {
+			value := 0
+			if state == actual {
+				value = 1
+			}
+			if _, err := fmt.Fprintf(out, "%s{%s,status=\"%s\"} %d %s\n", m.name, labels, state, value, timestamp); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	value := v.Value
+	if m.scale != 0 {
+		value *= m.scale
+	}
+	_, err := fmt.Fprintf(out, "%s{%s} %s %s\n", m.name, labels, strconv.FormatFloat(value, 'g', -1, 64), timestamp)
+	return err
+}
+
+// exportHistory write samples in OpenMetrics text format under live metric names, labels and types
+//   - samples of every metric family must be together, they are collected in temporary files and copied to output
+func exportHistory(out io.Writer, from time.Time, to time.Time, filter *ValuesFilter) error {
+	families := make(map[string]*historyExportFamily)
+	defer func() {
+		for _, f := range families {
+			_ = f.file.Close()
+			_ = os.Remove(f.file.Name())
+		}
+	}()
+	metrics := make(map[string]historyMetric)
+	err := history.scan(from, to, filter, func(v *CounterValue) error {
+		key := objectKey(v.Object, v.Counter)
+		m, ok := metrics[key]
+		if !ok {
+			m = newHistoryMetric(v)
+			metrics[key] = m
+		}
+		f, ok := families[m.name]
+		if !ok {
+			file, err := os.CreateTemp("", "cucm_history_*.om")
+			if err != nil {
+				return err
+			}
+			f = &historyExportFamily{metric: m, file: file, buffer: bufio.NewWriter(file)}
+			families[m.name] = f
+		}
+		return m.write(f.buffer, v)
+	})
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		f := families[name]
+		family, metricType := f.metric.family()
+		if _, err = fmt.Fprintf(out, "# HELP %s %s\n# TYPE %s %s\n", family, openMetricsHelp(f.metric.help), family, metricType); err != nil {
+			return err
+		}
+		if err = f.buffer.Flush(); err != nil {
+			return err
+		}
+		if _, err = f.file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		if _, err = io.Copy(out, f.file); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprint(out, "# EOF\n")
+	return err
+}
+
+// apiHistoryExportHandler HTTP handler for /api/v1/history/export, samples in OpenMetrics text format for backfill
+func apiHistoryExportHandler(w http.ResponseWriter, r *http.Request) {
+	log.WithFields(log.Fields{"metricsUri": r.URL.Path, FieldRoutine: "apiHistoryExportHandler"}).Debugf("request %s", r.URL.Path)
+	from, to, filter, ok := historyRequest(w, r, time.Duration(config.History.RetentionDays)*24*time.Hour)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", "application/openmetrics-text; version=1.0.0; charset=utf-8")
+	w.Header().Set("Content-Disposition", "attachment; filename=\"cucm_history.om\"")
+	out := bufio.NewWriter(w)
+	if err := exportHistory(out, from, to, filter); err != nil {
+		log.WithFields(log.Fields{FieldRoutine: "apiHistoryExportHandler"}).Errorf("problem export history. Error: %s", err)
+		return
+	}
+	_ = out.Flush()
+}
+
+// openMetricsLabel escape label value for OpenMetrics text format
+func openMetricsLabel(value string) string {
+	return strings.NewReplacer("\\", "\\\\", "\"", "\\\"", "\n", "\\n").Replace(value)
+}
+
+// openMetricsHelp escape help text for OpenMetrics text format
+func openMetricsHelp(value string) string {
+	return strings.NewReplacer("\\", "\\\\", "\"", "\\\"", "\n", " ", "\r", " ").Replace(value)
+}
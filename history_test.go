@@ -0,0 +1,171 @@
+package main
+
+import (
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseHistoryLine(t *testing.T) {
+	v, ok := parseHistoryLine("1709280000123\tnode1\tCisco SIP\ttrunk01\tCallsActive\t3.5")
+	if !ok {
+		t.Fatalf("valid line isn't parsed")
+	}
+	if !v.Time.Equal(time.UnixMilli(1709280000123)) || v.Server != "node1" || v.Object != "Cisco SIP" ||
+		v.Instance != "trunk01" || v.Counter != "CallsActive" || v.Value != 3.5 || !v.Valid {
+		t.Errorf("line parsed as %+v", v)
+	}
+	for _, line := range []string{"", "1709280000123\tnode1\tCisco SIP\ttrunk01\tCallsActive", "x\tnode1\tMemory\t\t% Mem Used\t1",
+		"1709280000123\tnode1\tMemory\t\t% Mem Used\tx", "1709280000123\tnode1\tMemory\t\t% Mem Used\t1\t2"} {
+		if _, ok = parseHistoryLine(line); ok {
+			t.Errorf("invalid line %q is parsed", line)
+		}
+	}
+}
+
+// historyData session data with one valid and one not valid sample per counter
+func historyData(value float64) *SessionData {
+	return &SessionData{CollectData: []OneCollectData{
+		{Name: "\\\\node1\\Cisco CallManager\\CallsActive", Value: value, CStatus: "1"},
+		{Name: "\\\\node1\\Cisco SIP(trunk01)\\CallsActive", Value: value + 1, CStatus: "1"},
+		{Name: "\\\\node1\\Cisco SIP(trunk02)\\CallsActive", Value: value + 2, CStatus: "2"},
+	}}
+}
+
+func TestScanSegment(t *testing.T) {
+	directory := t.TempDir()
+	h, err := NewHistory(directory, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now().Truncate(time.Second)
+	for i := 0; i < 3; i++ {
+		h.write(start.Add(time.Duration(i)*time.Minute), historyData(float64(i)))
+	}
+	h.close()
+	segments := h.segments()
+	if len(segments) != 1 {
+		t.Fatalf("history has %d segments, want 1", len(segments))
+	}
+
+	var values []*CounterValue
+	collect := func(v *CounterValue) error {
+		values = append(values, v)
+		return nil
+	}
+	err = scanSegment(segments[0].fileName, start.Add(time.Minute), start.Add(2*time.Minute), NewValuesFilter("", "Cisco SIP", "", ""), collect)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// not valid samples aren't stored, range and filter limit samples
+	if len(values) != 2 || values[0].Instance != "trunk01" || values[0].Value != 2 || values[1].Value != 3 {
+		t.Fatalf("scan return %d samples %v", len(values), values)
+	}
+
+	// truncated segment is read to last complete line
+	content, err := os.ReadFile(segments[0].fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	truncated := filepath.Join(directory, "truncated"+HistoryFileSuffix)
+	if err = os.WriteFile(truncated, content[:len(content)-10], 0o600); err != nil {
+		t.Fatal(err)
+	}
+	values = nil
+	if err = scanSegment(truncated, start, start.Add(time.Hour), NewValuesFilter("", "", "", ""), collect); err != nil {
+		t.Fatal(err)
+	}
+	if len(values) == 0 || len(values) > 6 {
+		t.Errorf("truncated segment return %d samples", len(values))
+	}
+
+	// empty segment (created and not flushed) has no samples
+	empty := filepath.Join(directory, "empty"+HistoryFileSuffix)
+	if err = os.WriteFile(empty, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	values = nil
+	if err = scanSegment(empty, start, start.Add(time.Hour), NewValuesFilter("", "", "", ""), collect); err != nil || len(values) != 0 {
+		t.Errorf("empty segment return %d samples and error %v", len(values), err)
+	}
+}
+
+func TestHistoryFlushInterval(t *testing.T) {
+	h, err := NewHistory(t.TempDir(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.close()
+	start := time.Now()
+	h.write(start, historyData(1))
+	h.write(start.Add(HistoryFlushInterval/2), historyData(2))
+	if info, err := h.file.Stat(); err != nil || info.Size() != 0 {
+		t.Fatalf("segment flushed in flush interval")
+	}
+	h.write(start.Add(HistoryFlushInterval), historyData(3))
+	reader, err := os.Open(h.file.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = reader.Close() }()
+	if _, err = gzip.NewReader(reader); err != nil {
+		t.Errorf("segment isn't flushed after flush interval. Error: %s", err)
+	}
+}
+
+func TestExportHistory(t *testing.T) {
+	savedHistory, savedObjects, savedCallsActive := history, config.Objects, config.Metrics.CallsActive
+	defer func() {
+		history, config.Objects, config.Metrics.CallsActive = savedHistory, savedObjects, savedCallsActive
+	}()
+	config.Metrics.CallsActive = true
+	config.Objects = map[string]*ConfigObject{
+		"memory":          {Enabled: true},
+		"tomcatConnector": {Enabled: true},
+		"dbReplication":   {Enabled: true},
+	}
+	var err error
+	if history, err = NewHistory(t.TempDir(), 1); err != nil {
+		t.Fatal(err)
+	}
+	now := time.UnixMilli(1709280000000)
+	history.write(now, &SessionData{CollectData: []OneCollectData{
+		{Name: "\\\\node1\\Cisco CallManager\\CallsActive", Value: 5, CStatus: "1"},
+		{Name: "\\\\node1\\Memory\\Used KBytes", Value: 2, CStatus: "1"},
+		{Name: "\\\\node1\\Cisco Tomcat Connector(http-8443)\\Requests", Value: 10, CStatus: "1"},
+		{Name: "\\\\node1\\Number of Replicates Created and State of Replication(ReplicateCount)\\Replicate_State", Value: 2, CStatus: "1"},
+		{Name: "\\\\node1\\Cisco CallManager\\UnknownCounter", Value: 7, CStatus: "1"},
+	}})
+	history.close()
+
+	out := strings.Builder{}
+	if err = exportHistory(&out, now.Add(-time.Minute), now.Add(time.Minute), NewValuesFilter("", "", "", "")); err != nil {
+		t.Fatal(err)
+	}
+	export := out.String()
+	for _, want := range []string{
+		"# TYPE cucm_calls_active gauge\n",
+		"cucm_calls_active{server=\"node1\"} 5 1709280000.000\n",
+		"# TYPE cucm_memory_used_bytes gauge\n",
+		"cucm_memory_used_bytes{server=\"node1\"} 2048 1709280000.000\n",
+		"# TYPE cucm_tomcat_connector_requests counter\n",
+		"cucm_tomcat_connector_requests_total{server=\"node1\",connector=\"http-8443\"} 10 1709280000.000\n",
+		"cucm_db_replication_state{server=\"node1\",instance=\"ReplicateCount\",status=\"good\"} 1 1709280000.000\n",
+		"cucm_db_replication_state{server=\"node1\",instance=\"ReplicateCount\",status=\"unknown\"} 0 1709280000.000\n",
+		"cucm_perfmon_value{server=\"node1\",object=\"Cisco CallManager\",instance=\"\",counter=\"UnknownCounter\"} 7 1709280000.000\n",
+	} {
+		if !strings.Contains(export, want) {
+			t.Errorf("export doesn't contain %q", want)
+		}
+	}
+	if !strings.HasSuffix(export, "# EOF\n") {
+		t.Errorf("export doesn't end with EOF")
+	}
+	// samples of one family are together
+	if strings.Count(export, "# TYPE cucm_perfmon_value") != 1 {
+		t.Errorf("metric family cucm_perfmon_value is exported more than once")
+	}
+}
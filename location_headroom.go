@@ -0,0 +1,95 @@
+package main
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// LocationHeadroomGroup object with location bandwidth counters
+const LocationHeadroomGroup = "Cisco Locations LBM"
+
+// locationBandwidth available and maximum counter pair with headroom metric
+type locationBandwidth struct {
+	available string
+	maximum   string
+	desc      *prometheus.Desc
+}
+
+// LocationHeadroom compute ratio of available to maximum bandwidth for every location
+type LocationHeadroom struct {
+	mutex      sync.Mutex
+	bandwidths []*locationBandwidth
+	values     map[string]*locationValues // values latest counter values by server and location
+}
+
+// locationValues latest bandwidth counters of one location
+type locationValues struct {
+	labelValues []string
+	counters    map[string]float64
+}
+
+// NewLocationHeadroom create headroom collector for audio, video and immersive bandwidth
+func NewLocationHeadroom() *LocationHeadroom {
+	labels := []string{"server", "location"}
+	return &LocationHeadroom{
+		bandwidths: []*locationBandwidth{
+			{available: BandwidthAvailable, maximum: BandwidthMaximum,
+				desc: prometheus.NewDesc("cucm_location_bandwidth_headroom_ratio", "Ratio of available to maximum audio bandwidth of location", labels, nil)},
+			{available: VideoBandwidthAvailable, maximum: VideoBandwidthMaximum,
+				desc: prometheus.NewDesc("cucm_location_video_bandwidth_headroom_ratio", "Ratio of available to maximum video bandwidth of location", labels, nil)},
+			{available: ImmersiveVideoBandwidthAvailable, maximum: ImmersiveVideoBandwidthMaximum,
+				desc: prometheus.NewDesc("cucm_location_immersive_bandwidth_headroom_ratio", "Ratio of available to maximum immersive video bandwidth of location", labels, nil)},
+		},
+		values: make(map[string]*locationValues),
+	}
+}
+
+// observe store latest value of bandwidth counter for location
+func (l *LocationHeadroom) observe(server string, group string, counter string, value float64) {
+	object, location := splitInstance(group)
+	if object != LocationHeadroomGroup || location == "" {
+		return
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	key := strings.Join([]string{server, location}, "\x00")
+	v, ok := l.values[key]
+	if !ok {
+		v = &locationValues{labelValues: []string{server, location}, counters: make(map[string]float64)}
+		l.values[key] = v
+	}
+	v.counters[counter] = value
+}
+
+// removeInstance forget location removed from session
+func (l *LocationHeadroom) removeInstance(server string, location string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	delete(l.values, strings.Join([]string{server, location}, "\x00"))
+}
+
+// Describe implements prometheus.Collector
+func (l *LocationHeadroom) Describe(ch chan<- *prometheus.Desc) {
+	for _, b := range l.bandwidths {
+		ch <- b.desc
+	}
+}
+
+// Collect implements prometheus.Collector
+//   - ratio isn't exported when location has unlimited or zero maximum bandwidth
+func (l *LocationHeadroom) Collect(ch chan<- prometheus.Metric) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	for _, v := range l.values {
+		for _, b := range l.bandwidths {
+			available, okAvailable := v.counters[b.available]
+			maximum, okMaximum := v.counters[b.maximum]
+			if !okAvailable || !okMaximum || maximum <= 0 {
+				continue
+			}
+			ch <- prometheus.MustNewConstMetric(b.desc, prometheus.GaugeValue, available/maximum, v.labelValues...)
+		}
+	}
+}
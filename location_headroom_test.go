@@ -0,0 +1,38 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestLocationCounterCollected(t *testing.T) {
+	saved := config.Objects
+	defer func() { config.Objects = saved }()
+	config.Objects = map[string]*ConfigObject{
+		"locationsLbm": {Enabled: true, Counters: map[string]bool{BandwidthMaximum: false}},
+	}
+	object := supportedObject(LocationHeadroomGroup)
+	if object.counterEnabled(BandwidthMaximum) {
+		t.Errorf("disabled counter %s is exported", BandwidthMaximum)
+	}
+	if !object.counterCollected(BandwidthMaximum) {
+		t.Errorf("counter %s required for headroom isn't collected", BandwidthMaximum)
+	}
+	if object.counterCollected("BandwidthOversubscription") {
+		t.Errorf("not required disabled counter is collected")
+	}
+}
+
+func TestLocationHeadroomRemoveInstance(t *testing.T) {
+	l := NewLocationHeadroom()
+	l.observe("node1", "Cisco Locations LBM(Hub_None)", BandwidthAvailable, 500)
+	l.observe("node1", "Cisco Locations LBM(Hub_None)", BandwidthMaximum, 1000)
+	l.observe("node1", "Cisco Locations LBM(Branch)", BandwidthAvailable, 80)
+	l.observe("node1", "Cisco Locations LBM(Branch)", BandwidthMaximum, 100)
+	if count := len(collectMetrics(t, l)); count != 2 {
+		t.Fatalf("exported %d ratios, want 2", count)
+	}
+	l.removeInstance("node1", "Branch")
+	if count := len(collectMetrics(t, l)); count != 1 {
+		t.Errorf("exported %d ratios after remove location, want 1", count)
+	}
+}
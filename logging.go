@@ -19,6 +19,7 @@ const (
 	FieldSession       = "session"     // define session ID
 	FieldMonitorName   = "monitorName" // define monitor name field
 	FieldSessionId     = "sessionId"   // define name for session ID field
+	FieldErrorKind     = "errorKind"   // define API error classification
 	LogRequestDuration = false         // define if logg duration for every request
 )
 
@@ -95,6 +95,11 @@ func monitoringProcess() {
 	signal.Notify(quit, os.Interrupt)
 
 	log.WithFields(log.Fields{FieldRoutine: "monitoringProcess"}).Trace("read performance counters and description")
+	prometheusCreateApiMetrics()
+	prometheusCreateBusyHour()
+	if busyHour != nil {
+		defer busyHour.close()
+	}
 	monitors = *NewPerfMonServers()
 	errMonitor := monitors.ListAllCounters()
 	if errMonitor != nil {
@@ -102,6 +107,15 @@ func monitoringProcess() {
 		log.WithFields(log.Fields{FieldRoutine: "monitoringProcess"}).Fatal("problem collect counters from server")
 	}
 
+	if config.History.Enabled {
+		var errHistory error
+		if history, errHistory = NewHistory(config.History.Directory, config.History.RetentionDays); errHistory != nil {
+			log.WithFields(log.Fields{FieldRoutine: "monitoringProcess"}).Errorf("problem open history directory %s, history is disabled. Error: %s", config.History.Directory, errHistory)
+		} else {
+			defer history.close()
+		}
+	}
+
 	log.WithFields(log.Fields{FieldRoutine: "monitoringProcess"}).Trace("start web server and gracefully shutdown GO routines")
 	srv := newWebServer(quit)
 	go gracefullyShutdown(srv, quit, done)
@@ -135,13 +149,16 @@ func monitoringProcess() {
 			if err == nil {
 				err = monitors.CollectSessionData()
 			}
+			if err == nil {
+				monitors.RefreshInstances(time.Now())
+			}
 			if err != nil {
 				log.WithFields(log.Fields{FieldRoutine: "monitoringProcess"}).Info("problem read data close session")
 				monitors.CloseSession()
 			} else {
 				log.WithFields(log.Fields{FieldRoutine: "monitoringProcess"}).Trace("collect session data")
 			}
-			durationWait = time.Second*time.Duration(config.SleepBetweenRequest) - time.Now().Sub(roundStartTime)
+			durationWait = config.PollInterval() - time.Now().Sub(roundStartTime)
 			if !monitors.ExistSession() {
 				durationWait = time.Second * 60 // wait for the next try to connect to the server
 			} else if durationWait < 1*time.Millisecond {
@@ -190,6 +207,10 @@ func main() {
 	kingpin.Parse()
 	err := config.LoadFile(*configFile)
 	initLog()
+	if err == nil {
+		// standard groups are switched only for valid configuration
+		productProfile(config.Product).apply()
+	}
 
 	if *showConfig {
 		fmt.Println(config.print())
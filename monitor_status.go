@@ -0,0 +1,178 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// StatusErrorsSize number of recent errors kept in ring buffer
+const StatusErrorsSize = 50
+
+// StatusError one recent error of PerfMon API
+type StatusError struct {
+	Time      time.Time `json:"time"`
+	Operation string    `json:"operation"`
+	Kind      string    `json:"kind"`
+	Message   string    `json:"message"`
+}
+
+// NodeStatus connectivity of one monitored node based on last collection
+type NodeStatus struct {
+	Server        string    `json:"server"`
+	Connected     bool      `json:"connected"`
+	LastSeen      time.Time `json:"lastSeen"`
+	Values        int       `json:"values"`
+	InvalidValues int       `json:"invalidValues"`
+}
+
+// RateState actual state of API rate limiter
+type RateState struct {
+	Requests    int       `json:"requests"`
+	Limit       int       `json:"limit"`
+	WindowStart time.Time `json:"windowStart"`
+}
+
+// StatusSnapshot copy of program status for web pages and API
+type StatusSnapshot struct {
+	Connected           bool          `json:"connected"`
+	SessionStart        time.Time     `json:"sessionStart"`
+	SessionAgeSeconds   float64       `json:"sessionAgeSeconds"`
+	LastCollection      time.Time     `json:"lastCollection"`
+	LastDurationSeconds float64       `json:"lastDurationSeconds"`
+	Collections         uint64        `json:"collections"`
+	Requests            uint64        `json:"requests"`
+	Responses           uint64        `json:"responses"`
+	ResponseErrors      uint64        `json:"responseErrors"`
+	Rate                RateState     `json:"rateLimiter"`
+	Nodes               []NodeStatus  `json:"nodes"`
+	Errors              []StatusError `json:"errors"`
+	SessionAge          time.Duration `json:"-"`
+	LastDuration        time.Duration `json:"-"`
+}
+
+// MonitorStatus status of monitoring process, session, nodes and recent errors
+type MonitorStatus struct {
+	mutex          sync.RWMutex
+	sessionStart   time.Time
+	lastCollection time.Time
+	lastDuration   time.Duration
+	collections    uint64
+	nodes          map[string]*NodeStatus
+	errors         []StatusError // errors ring buffer
+	next           int           // next position in errors ring buffer
+}
+
+// monitorStatus actual status of monitoring
+var monitorStatus = NewMonitorStatus()
+
+// NewMonitorStatus create empty monitoring status
+func NewMonitorStatus() *MonitorStatus {
+	return &MonitorStatus{nodes: make(map[string]*NodeStatus), errors: make([]StatusError, 0, StatusErrorsSize)}
+}
+
+// sessionOpened store time when session is opened
+func (m *MonitorStatus) sessionOpened(now time.Time) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.sessionStart = now
+}
+
+// sessionClosed mark all nodes as disconnected
+func (m *MonitorStatus) sessionClosed() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.sessionStart = time.Time{}
+	for _, node := range m.nodes {
+		node.Connected = false
+	}
+}
+
+// collected store result of one collection, node is connected when it returns any valid value
+func (m *MonitorStatus) collected(start time.Time, data *SessionData) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.lastCollection = start
+	m.lastDuration = time.Since(start)
+	m.collections++
+	for _, node := range m.nodes {
+		node.Values = 0
+		node.InvalidValues = 0
+	}
+	for i := range data.CollectData {
+		server, _, _, err := data.CollectData[i].splitName()
+		if err != nil {
+			continue
+		}
+		node := m.node(server)
+		if data.CollectData[i].valid() {
+			node.Values++
+		} else {
+			node.InvalidValues++
+		}
+	}
+	for _, node := range m.nodes {
+		node.Connected = node.Values > 0
+		if node.Connected {
+			node.LastSeen = start
+		}
+	}
+}
+
+// node status of node, create new one when not exists
+func (m *MonitorStatus) node(server string) *NodeStatus {
+	node, ok := m.nodes[server]
+	if !ok {
+		node = &NodeStatus{Server: server}
+		m.nodes[server] = node
+	}
+	return node
+}
+
+// addError store error to ring buffer
+func (m *MonitorStatus) addError(operation string, kind string, message string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	e := StatusError{Time: time.Now(), Operation: operation, Kind: kind, Message: message}
+	if len(m.errors) < StatusErrorsSize {
+		m.errors = append(m.errors, e)
+	} else {
+		m.errors[m.next] = e
+	}
+	m.next = (m.next + 1) % StatusErrorsSize
+}
+
+// snapshot copy of actual status, errors are sorted from newest
+func (m *MonitorStatus) snapshot() StatusSnapshot {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	now := time.Now()
+	s := StatusSnapshot{
+		Connected:      monitors.client != nil && monitors.ExistSession(),
+		SessionStart:   m.sessionStart,
+		LastCollection: m.lastCollection,
+		LastDuration:   m.lastDuration,
+		Collections:    m.collections,
+		Rate:           rateRequest.state(),
+		Nodes:          make([]NodeStatus, 0, len(config.MonitorNames)),
+		Errors:         make([]StatusError, 0, len(m.errors)),
+	}
+	if monitors.client != nil {
+		s.Requests, s.Responses, s.ResponseErrors = monitors.client.requests.Load(), monitors.client.responses.Load(), monitors.client.responseErrors.Load()
+	}
+	if !m.sessionStart.IsZero() {
+		s.SessionAge = now.Sub(m.sessionStart).Round(time.Second)
+		s.SessionAgeSeconds = s.SessionAge.Seconds()
+	}
+	s.LastDurationSeconds = m.lastDuration.Seconds()
+	for _, server := range config.MonitorNames {
+		if node, ok := m.nodes[server]; ok {
+			s.Nodes = append(s.Nodes, *node)
+		} else {
+			s.Nodes = append(s.Nodes, NodeStatus{Server: server})
+		}
+	}
+	for i := 0; i < len(m.errors); i++ {
+		s.Errors = append(s.Errors, m.errors[(m.next-1-i+StatusErrorsSize)%StatusErrorsSize])
+	}
+	return s
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+// objectMetric Prometheus metric for one counter of supported object
+type objectMetric struct {
+	gauge     *prometheus.GaugeVec   // gauge for actual values, nil for cumulative counters
+	counter   *prometheus.CounterVec // counter for cumulative values, nil for gauges
+	channels  *ChannelStatus         // channels status counts for channel status counters (shared by all channels) or state set
+	channel   int                    // channel number of channel status counter
+	instanced bool                   // instanced metric has instance label
+	rate      bool                   // rate counter, first sample of series in session is skipped
+	scale     float64                // scale multiplier for conversion to base unit, 0 is without conversion
+}
+
+var (
+	// objectMetrics metrics of supported objects by object key (group and counter)
+	objectMetrics map[string]*objectMetric
+	// objectActual actual presented value of cumulative object counters by series
+	objectActual map[string]float64
+	// objectChannels channel status collectors by Prometheus name
+	objectChannels map[string]*ChannelStatus
+	// objectSeen series of rate counters with at least one sample in session
+	objectSeen map[string]bool
+)
+
+// objectMetricsCreate create metrics for all counters of supported objects registered on any server
+func objectMetricsCreate() {
+	log.WithFields(log.Fields{FieldRoutine: "objectMetricsCreate"}).Debug("prepare new object metrics")
+	objectMetrics = make(map[string]*objectMetric)
+	objectActual = make(map[string]float64)
+	objectChannels = make(map[string]*ChannelStatus)
+	objectSeen = make(map[string]bool)
+	for _, server := range monitors.monitors {
+		for _, group := range server.counterList.group {
+			object := supportedObject(group.groupName)
+			if object == nil {
+				continue
+			}
+			for _, name := range group.counterName {
+				key := objectKey(group.groupName, name.name)
+				if _, ok := objectMetrics[key]; ok {
+					continue
+				}
+				counter := object.counter(name.name)
+				if counter == nil || !object.counterEnabled(name.name) {
+					continue
+				}
+				objectMetrics[key] = newObjectMetric(object, counter, group.multiInstance)
+			}
+		}
+	}
+}
+
+// newObjectMetric create and register metric for counter of object
+func newObjectMetric(object *ObjectCounters, counter *Counters, multiInstance bool) *objectMetric {
+	name := object.metricName(counter)
+	details, err := monitors.GetGroupCounterDetails(object.groupName, counter.allowedCounterName)
+	if err != nil {
+		log.WithFields(log.Fields{FieldRoutine: "newObjectMetric", FieldMetricsName: name}).Debugf("not defined description for %s", counter.allowedCounterName)
+	}
+	instanced := multiInstance && len(object.instanceLabel) > 0
+	labels := []string{"server"}
+	if instanced {
+		labels = append(labels, object.instanceLabel)
+	}
+	m := &objectMetric{instanced: instanced, channel: counter.channel, rate: counter.rate, scale: counter.scale}
+	if len(counter.states) > 0 {
+		m.channels = NewChannelStatus(name, details.description, labels, counter.states)
+		objectChannels[name] = m.channels
+		prometheus.MustRegister(m.channels)
+		return m
+	}
+	if counter.channel > 0 {
+		m.channels = objectChannels[name]
+		if m.channels == nil {
+			m.channels = NewChannelStatus(name, fmt.Sprintf("Number of channels by status (%s)", object.groupName), labels, ChannelStatusNames)
+			objectChannels[name] = m.channels
+			prometheus.MustRegister(m.channels)
+		}
+		return m
+	}
+	if counter.cumulative {
+		m.counter = prometheus.NewCounterVec(prometheus.CounterOpts{Name: name, Help: details.description}, labels)
+		prometheus.MustRegister(m.counter)
+		return m
+	}
+	m.gauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: name, Help: details.description}, labels)
+	prometheus.MustRegister(m.gauge)
+	if gaugeSampling != nil {
+		gaugeSampling.addCounter(objectKey(object.groupName, counter.allowedCounterName), name, details.description, labels)
+	}
+	return m
+}
+
+// objectMetricsRemove remove all object metrics from prometheus
+func objectMetricsRemove() {
+	log.WithFields(log.Fields{FieldRoutine: "objectMetricsRemove"}).Debug("remove object metrics")
+	for _, m := range objectMetrics {
+		if m.counter != nil {
+			prometheus.Unregister(m.counter)
+		} else if m.gauge != nil {
+			prometheus.Unregister(m.gauge)
+		}
+	}
+	for _, c := range objectChannels {
+		prometheus.Unregister(c)
+	}
+	objectMetrics = make(map[string]*objectMetric)
+	objectActual = make(map[string]float64)
+	objectChannels = make(map[string]*ChannelStatus)
+	objectSeen = make(map[string]bool)
+}
+
+// objectMetricsProcess update metric of supported object, returns false when counter isn't object counter
+//   - rate counters are valid only from second sample in session
+//   - values of counters with scale are converted to base unit
+func objectMetricsProcess(server string, group string, counter string, value float64) bool {
+	object, instance := splitInstance(group)
+	m, ok := objectMetrics[objectKey(object, counter)]
+	if !ok {
+		return false
+	}
+	labels := []string{server}
+	if m.instanced {
+		labels = append(labels, instance)
+	}
+	series := fmt.Sprintf("%s\\%s", objectKey(object, counter), strings.Join(labels, "\\"))
+	if m.rate {
+		first := !objectSeen[series]
+		objectSeen[series] = true
+		if first {
+			return true
+		}
+	}
+	if m.channels != nil {
+		m.channels.observe(m.channel, value, labels...)
+		return true
+	}
+	if m.scale != 0 {
+		value *= m.scale
+	}
+	if m.gauge != nil {
+		m.gauge.WithLabelValues(labels...).Set(value)
+		if gaugeSampling != nil {
+			gaugeSampling.observe(objectKey(object, counter), value, labels...)
+		}
+		return true
+	}
+	newVal := value - objectActual[series]
+	if newVal < 0 {
+		// counter reset after service restart
+		newVal = value
+	}
+	m.counter.WithLabelValues(labels...).Add(newVal)
+	objectActual[series] = value
+	return true
+}
+
+// objectMetricsRemoveInstance remove series of instance removed from session
+func objectMetricsRemoveInstance(server string, group string, instance string) {
+	for key, m := range objectMetrics {
+		if !strings.HasPrefix(key, objectKey(group, "")) || !m.instanced {
+			continue
+		}
+		if m.gauge != nil {
+			m.gauge.DeleteLabelValues(server, instance)
+			if gaugeSampling != nil {
+				gaugeSampling.remove(key, server, instance)
+			}
+		}
+		if m.counter != nil {
+			m.counter.DeleteLabelValues(server, instance)
+		}
+		if m.channels != nil {
+			// channel status collector can be shared by more counters, removing is idempotent
+			m.channels.removeInstance(server, instance)
+		}
+		series := fmt.Sprintf("%s\\%s", key, strings.Join([]string{server, instance}, "\\"))
+		delete(objectActual, series)
+		delete(objectSeen, series)
+	}
+	if sipTrunkFailures != nil && group == SipTrunkGroup {
+		sipTrunkFailures.removeInstance(server, instance)
+	}
+	if locationHeadroom != nil && group == LocationHeadroomGroup {
+		locationHeadroom.removeInstance(server, instance)
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestObjectMetricsProcessScale(t *testing.T) {
+	object := supportedObject("Memory")
+	counter := object.counter("Total KBytes")
+	objectMetrics = map[string]*objectMetric{}
+	objectActual = make(map[string]float64)
+	objectSeen = make(map[string]bool)
+	m := &objectMetric{scale: counter.scale}
+	m.gauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: counter.prometheusName, Help: "help"}, []string{"server"})
+	objectMetrics[objectKey("Memory", "Total KBytes")] = m
+
+	if !objectMetricsProcess("node1", "Memory", "Total KBytes", 2048) {
+		t.Fatal("counter isn't processed as object counter")
+	}
+	if got := metricValue(collectMetrics(t, m.gauge.WithLabelValues("node1"))[0]); got != 2048*1024 {
+		t.Errorf("exported %v bytes, want %v", got, 2048*1024)
+	}
+}
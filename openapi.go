@@ -0,0 +1,196 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/version"
+)
+
+// OpenApiVersion version of OpenAPI specification used by generated schema
+const OpenApiVersion = "3.0.3"
+
+// ApiParameter query parameter of API endpoint
+type ApiParameter struct {
+	Name        string
+	Description string
+}
+
+// ApiEndpoint description of one API endpoint used for generate OpenAPI schema
+type ApiEndpoint struct {
+	Path        string
+	Summary     string
+	Parameters  []ApiParameter
+	ContentType string      // response content type, default is application/json
+	Response    interface{} // example of response, schema is generated from its type
+}
+
+// valuesParameters parameters of values filter
+var valuesParameters = []ApiParameter{
+	{Name: "server", Description: "node name, wildcards are allowed"},
+	{Name: "object", Description: "object name, wildcards are allowed"},
+	{Name: "instance", Description: "object instance, wildcards are allowed"},
+	{Name: "counter", Description: "counter name, wildcards are allowed"},
+}
+
+var (
+	historyStart = ApiParameter{Name: "start", Description: "start of range, unix time in seconds or RFC3339"}
+	historyEnd   = ApiParameter{Name: "end", Description: "end of range, unix time in seconds or RFC3339, default is now"}
+)
+
+// apiEndpoints all documented API endpoints
+var apiEndpoints = []ApiEndpoint{
+	{
+		Path:       ApiPrefix + "/values",
+		Summary:    "Last collected values of counters per node, object instance and counter",
+		Parameters: valuesParameters,
+		Response:   ApiValues{},
+	},
+	{Path: ApiPrefix + "/status", Summary: "Monitoring session, nodes, rate limiter and recent errors", Response: StatusSnapshot{}},
+	{Path: ApiPrefix + "/config", Summary: "Actual configuration, passwords are redacted", Response: Config{}},
+	{
+		Path:        ApiPrefix + "/stream",
+		Summary:     "Server sent events, event values with changed values after every collection and event heartbeat when session is down",
+		Parameters:  valuesParameters,
+		ContentType: "text/event-stream",
+		Response:    StreamValues{},
+	},
+	{
+		Path:       ApiPrefix + "/history",
+		Summary:    "Samples from local history in time range [start, end], default range is last hour",
+		Parameters: append([]ApiParameter{historyStart, historyEnd}, valuesParameters...),
+		Response:   HistoryResult{},
+	},
+	{
+		Path:        ApiPrefix + "/history/export",
+		Summary:     "Samples from local history in OpenMetrics text format for promtool tsdb create-blocks-from openmetrics, default range is whole retention",
+		Parameters:  append([]ApiParameter{historyStart, historyEnd}, valuesParameters...),
+		ContentType: "application/openmetrics-text",
+		Response:    "",
+	},
+	{Path: ApiPrefix + "/openapi.json", Summary: "OpenAPI schema of this API", Response: map[string]interface{}{}},
+}
+
+// schemaGenerator generate JSON schemas from Go types, named structures are stored as components
+type schemaGenerator struct {
+	schemas map[string]interface{}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// openApiDocument generate OpenAPI document for all API endpoints
+func openApiDocument() map[string]interface{} {
+	g := &schemaGenerator{schemas: make(map[string]interface{})}
+	paths := make(map[string]interface{})
+	for _, e := range apiEndpoints {
+		paths[e.Path] = map[string]interface{}{"get": g.operation(e)}
+	}
+	return map[string]interface{}{
+		"openapi": OpenApiVersion,
+		"info": map[string]interface{}{
+			"title":   applicationName,
+			"version": version.Version,
+		},
+		"paths":      paths,
+		"components": map[string]interface{}{"schemas": g.schemas},
+	}
+}
+
+// operation OpenAPI operation of endpoint
+func (g *schemaGenerator) operation(e ApiEndpoint) map[string]interface{} {
+	parameters := make([]interface{}, 0, len(e.Parameters))
+	for _, p := range e.Parameters {
+		parameters = append(parameters, map[string]interface{}{
+			"name":        p.Name,
+			"in":          "query",
+			"required":    false,
+			"description": p.Description,
+			"schema":      map[string]interface{}{"type": "string"},
+		})
+	}
+	contentType := e.ContentType
+	if len(contentType) == 0 {
+		contentType = "application/json"
+	}
+	responses := map[string]interface{}{
+		"200": map[string]interface{}{
+			"description": "OK",
+			"content":     map[string]interface{}{contentType: map[string]interface{}{"schema": g.schema(reflect.TypeOf(e.Response))}},
+		},
+	}
+	if len(e.Parameters) > 0 {
+		responses["400"] = map[string]interface{}{
+			"description": "Invalid parameter",
+			"content":     map[string]interface{}{"application/json": map[string]interface{}{"schema": g.schema(reflect.TypeOf(ApiErrorResponse{}))}},
+		}
+	}
+	return map[string]interface{}{"summary": e.Summary, "parameters": parameters, "responses": responses}
+}
+
+// schema JSON schema of type, named structures are referenced from components
+func (g *schemaGenerator) schema(t reflect.Type) map[string]interface{} {
+	if t == nil {
+		return map[string]interface{}{}
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		if len(t.Name()) == 0 {
+			return g.object(t)
+		}
+		if _, ok := g.schemas[t.Name()]; !ok {
+			g.schemas[t.Name()] = map[string]interface{}{}
+			g.schemas[t.Name()] = g.object(t)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + t.Name()}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	}
+	return map[string]interface{}{}
+}
+
+// object JSON schema of structure from exported fields and their JSON names, embedded structures are inlined
+func (g *schemaGenerator) object(t reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{})
+	g.properties(t, properties)
+	return map[string]interface{}{"type": "object", "properties": properties}
+}
+
+// properties add properties of structure fields
+func (g *schemaGenerator) properties(t reflect.Type, properties map[string]interface{}) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && f.Type.Kind() == reflect.Struct && len(name) == 0 {
+			g.properties(f.Type, properties)
+			continue
+		}
+		if len(name) == 0 {
+			name = f.Name
+		}
+		properties[name] = g.schema(f.Type)
+	}
+}
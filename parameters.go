@@ -8,6 +8,7 @@ import (
 	"path"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/alecthomas/kingpin/v2"
 	log "github.com/sirupsen/logrus"
@@ -15,17 +16,25 @@ import (
 )
 
 type Config struct {
-	MonitorNames        []string       `yaml:"monitor_names" json:"monitor_names"`
-	Metrics             MetricsEnabled `yaml:"metrics" json:"metrics"`
-	Log                 ConfigLog      `yaml:"log" json:"log"`
-	ApiAddress          string         `yaml:"apiAddress" json:"apiAddress"`
-	ApiUser             string         `yaml:"apiUser" json:"apiUser"`
-	ApiPassword         string         `yaml:"apiPwd" json:"apiPwd"`
-	Port                int            `yaml:"port" json:"port"`
-	IgnoreCertificate   bool           `yaml:"ignoreCertificate" json:"ignoreCertificate"`
-	ApiTimeout          int            `yaml:"apiTimeout" json:"apiTimeout"`
-	AllowStop           bool           `yaml:"allowStop" json:"allowStop"`
-	SleepBetweenRequest int            `yaml:"sleepBetweenRequest" json:"sleepBetweenRequest"`
+	MonitorNames        []string                 `yaml:"monitor_names" json:"monitor_names"`
+	Metrics             MetricsEnabled           `yaml:"metrics" json:"metrics"`
+	Log                 ConfigLog                `yaml:"log" json:"log"`
+	ApiAddress          string                   `yaml:"apiAddress" json:"apiAddress"`
+	ApiUser             string                   `yaml:"apiUser" json:"apiUser"`
+	ApiPassword         string                   `yaml:"apiPwd" json:"apiPwd"`
+	Port                int                      `yaml:"port" json:"port"`
+	IgnoreCertificate   bool                     `yaml:"ignoreCertificate" json:"ignoreCertificate"`
+	ApiTimeout          int                      `yaml:"apiTimeout" json:"apiTimeout"`
+	AllowStop           bool                     `yaml:"allowStop" json:"allowStop"`
+	SleepBetweenRequest int                      `yaml:"sleepBetweenRequest" json:"sleepBetweenRequest"`
+	Sampling            ConfigSampling           `yaml:"sampling" json:"sampling"`
+	BusyHour            ConfigBusyHour           `yaml:"busyHour" json:"busyHour"`
+	Aggregation         []AggregationRule        `yaml:"aggregation" json:"aggregation"`
+	Objects             map[string]*ConfigObject `yaml:"objects" json:"objects"`
+	Product             string                   `yaml:"product" json:"product"`
+	InstanceRefresh     int                      `yaml:"instanceRefresh" json:"instanceRefresh"`
+	Query               ConfigQuery              `yaml:"query" json:"query"`
+	History             ConfigHistory            `yaml:"history" json:"history"`
 }
 
 type MetricsEnabled struct {
@@ -69,6 +78,16 @@ type MetricsEnabled struct {
 	MTPResourceActive                         bool `yaml:"mtpResourceActive" json:"mtpResourceActive"`
 	MTPResourceAvailable                      bool `yaml:"mtpResourceAvailable" json:"mtpResourceAvailable"`
 	MTPResourceTotal                          bool `yaml:"mtpResourceTotal" json:"mtpResourceTotal"`
+	BRIChannelsActive                         bool `yaml:"briChannelsActive" json:"briChannelsActive"`
+	BRISpansInService                         bool `yaml:"briSpansInService" json:"briSpansInService"`
+	FXOPortsActive                            bool `yaml:"fxoPortsActive" json:"fxoPortsActive"`
+	FXOPortsInService                         bool `yaml:"fxoPortsInService" json:"fxoPortsInService"`
+	FXSPortsActive                            bool `yaml:"fxsPortsActive" json:"fxsPortsActive"`
+	FXSPortsInService                         bool `yaml:"fxsPortsInService" json:"fxsPortsInService"`
+	PRIChannelsActive                         bool `yaml:"priChannelsActive" json:"priChannelsActive"`
+	PRISpansInService                         bool `yaml:"priSpansInService" json:"priSpansInService"`
+	T1ChannelsActive                          bool `yaml:"t1ChannelsActive" json:"t1ChannelsActive"`
+	T1SpansInService                          bool `yaml:"t1SpansInService" json:"t1SpansInService"`
 	RegisteredAnalogAccess                    bool `yaml:"registeredAnalogAccess" json:"registeredAnalogAccess"`
 	RegisteredMGCPGateway                     bool `yaml:"registeredMGCPGateway" json:"registeredMGCPGateway"`
 	RegisteredOtherStationDevices             bool `yaml:"registeredOtherStationDevices" json:"registeredOtherStationDevices"`
@@ -118,6 +137,45 @@ type ConfigLog struct {
 	Quiet          bool   `json:"quiet" yaml:"quiet"`                   // Logging quiet - output only to file or only panic
 }
 
+type ConfigSampling struct {
+	Enabled  bool `json:"enabled" yaml:"enabled"`   // poll data more often than scrape and export min/max/avg of gauges
+	Interval int  `json:"interval" yaml:"interval"` // seconds between collect data when sampling is enabled
+	Window   int  `json:"window" yaml:"window"`     // seconds of rolling window for min/max/avg
+}
+
+type ConfigBusyHour struct {
+	Enabled   bool   `json:"enabled" yaml:"enabled"`     // compute busy hour call attempts and daily peak of active calls
+	StateFile string `json:"stateFile" yaml:"stateFile"` // file where computed state is stored between restarts
+}
+
+type ConfigQuery struct {
+	Enabled  bool   `json:"enabled" yaml:"enabled"`   // enable ad-hoc counter query endpoint /query
+	User     string `json:"user" yaml:"user"`         // user for basic authentication of query endpoint
+	Password string `json:"password" yaml:"password"` // password for basic authentication of query endpoint
+}
+
+type ConfigHistory struct {
+	Enabled       bool   `json:"enabled" yaml:"enabled"`             // store every collected sample to local history
+	Directory     string `json:"directory" yaml:"directory"`         // directory for history segment files
+	RetentionDays int    `json:"retentionDays" yaml:"retentionDays"` // number of days kept in history
+}
+
+type AggregationRule struct {
+	Counter    string `json:"counter" yaml:"counter"`       // counter name from supported metrics, i.e. RegisteredHardwarePhones
+	Function   string `json:"function" yaml:"function"`     // aggregation function sum, avg, min or max. Default is sum
+	ShareRatio bool   `json:"shareRatio" yaml:"shareRatio"` // export per-node share of cluster sum
+}
+
+type ConfigObject struct {
+	Enabled  bool              `json:"enabled" yaml:"enabled"`   // export counters of object
+	Include  string            `json:"include" yaml:"include"`   // regular expression for exported instances, empty allow all
+	Exclude  string            `json:"exclude" yaml:"exclude"`   // regular expression for not exported instances, empty exclude none
+	Counters map[string]bool   `json:"counters" yaml:"counters"` // enable or disable counters, overwrite counter defaults
+	Names    map[string]string `json:"names" yaml:"names"`       // Prometheus metric names of counters, overwrite default names
+	include  *regexp.Regexp
+	exclude  *regexp.Regexp
+}
+
 type Intervals struct {
 	Default int
 	Min     int
@@ -127,12 +185,17 @@ type Intervals struct {
 var (
 	showConfig               = kingpin.Flag("config.show", "Show actual configuration and ends").Default("false").Bool()
 	configFile               = kingpin.Flag("config.file", "Configuration file default is \"server.yml\".").PlaceHolder("cfg.yml").Default("server.yml").String()
-	LogMaxSize               = Intervals{Default: 50, Min: 1, Max: 5000}       // Limits and defaults for Log MaxSize
-	LogMaxBackups            = Intervals{Default: 5, Min: 0, Max: 100}         // Limits and defaults for Log MaxBackups
-	LogMaxAge                = Intervals{Default: 30, Min: 1, Max: 365}        // Limits and defaults for Log MaxAge
-	PortLimits               = Intervals{Default: 9717, Min: 1024, Max: 65535} // Limits and defaults for ports
-	ApiTimeoutLimit          = Intervals{Default: 5, Min: 1, Max: 30}          // Limits and defaults for API Timeouts in sec
-	SleepBetweenRequestLimit = Intervals{Default: 30, Min: 5, Max: 120}        // Limits and defaults for sleep between API requests in  sec
+	LogMaxSize               = Intervals{Default: 50, Min: 1, Max: 5000}        // Limits and defaults for Log MaxSize
+	LogMaxBackups            = Intervals{Default: 5, Min: 0, Max: 100}          // Limits and defaults for Log MaxBackups
+	LogMaxAge                = Intervals{Default: 30, Min: 1, Max: 365}         // Limits and defaults for Log MaxAge
+	PortLimits               = Intervals{Default: 9717, Min: 1024, Max: 65535}  // Limits and defaults for ports
+	ApiTimeoutLimit          = Intervals{Default: 5, Min: 1, Max: 30}           // Limits and defaults for API Timeouts in sec
+	SleepBetweenRequestLimit = Intervals{Default: 30, Min: 5, Max: 120}         // Limits and defaults for sleep between API requests in  sec
+	SamplingIntervalLimit    = Intervals{Default: 5, Min: 2, Max: 60}           // Limits and defaults for sampling interval in sec
+	SamplingWindowLimit      = Intervals{Default: 60, Min: 10, Max: 3600}       // Limits and defaults for sampling window in sec
+	HistoryRetentionLimit    = Intervals{Default: 7, Min: 1, Max: 90}           // Limits and defaults for history retention in days
+	InstanceRefreshLimit     = Intervals{Default: 15, Min: 0, Max: 1440}        // Limits and defaults for refresh of object instances in min, 0 disable it
+	metricNameRegex          = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`) // valid Prometheus metric name
 
 	config = &Config{
 		Metrics: MetricsEnabled{
@@ -176,6 +239,16 @@ var (
 			MTPResourceActive:                         false,
 			MTPResourceAvailable:                      false,
 			MTPResourceTotal:                          true,
+			BRIChannelsActive:                         false,
+			BRISpansInService:                         false,
+			FXOPortsActive:                            false,
+			FXOPortsInService:                         false,
+			FXSPortsActive:                            false,
+			FXSPortsInService:                         false,
+			PRIChannelsActive:                         false,
+			PRISpansInService:                         false,
+			T1ChannelsActive:                          false,
+			T1SpansInService:                          false,
 			RegisteredAnalogAccess:                    false,
 			RegisteredMGCPGateway:                     false,
 			RegisteredOtherStationDevices:             false,
@@ -223,6 +296,9 @@ var (
 			MaxAge:         LogMaxAge.Default,
 			Quiet:          false,
 		},
+		Aggregation:         []AggregationRule{},
+		Objects:             map[string]*ConfigObject{},
+		Product:             ProductCucm,
 		MonitorNames:        []string{},
 		ApiAddress:          "",
 		ApiUser:             "",
@@ -232,6 +308,21 @@ var (
 		ApiTimeout:          15,
 		AllowStop:           false,
 		SleepBetweenRequest: 30,
+		InstanceRefresh:     InstanceRefreshLimit.Default,
+		Sampling: ConfigSampling{
+			Enabled:  false,
+			Interval: SamplingIntervalLimit.Default,
+			Window:   SamplingWindowLimit.Default,
+		},
+		BusyHour: ConfigBusyHour{
+			Enabled:   false,
+			StateFile: BusyHourDefaultStateFile,
+		},
+		History: ConfigHistory{
+			Enabled:       false,
+			Directory:     HistoryDefaultDirectory,
+			RetentionDays: HistoryRetentionLimit.Default,
+		},
 	}
 	apiServer = kingpin.Flag("api.address", "CUCM Server FQDN or IP address.").PlaceHolder("server").Default("").String()
 	apiUser   = kingpin.Flag("api.user", "CUCM user with access to PerfMON data.").PlaceHolder("User").Default("").String()
@@ -294,14 +385,139 @@ func (c *Config) Validate() (err error) {
 	if !SleepBetweenRequestLimit.Validate(c.SleepBetweenRequest) {
 		return errors.New("defined sleep between request is not valid")
 	}
+	if !InstanceRefreshLimit.Validate(c.InstanceRefresh) {
+		return errors.New("defined instance refresh is not valid")
+	}
+
+	if productProfile(c.Product) == nil {
+		return fmt.Errorf("product %s isn't supported", c.Product)
+	}
 
 	// validate child
 	if err = c.Log.Validate(); err != nil {
 		return err
 	}
+	if err = c.Sampling.Validate(); err != nil {
+		return err
+	}
+	if err = c.validateRateBudget(); err != nil {
+		return err
+	}
+	if err = c.BusyHour.Validate(); err != nil {
+		return err
+	}
+	if err = c.Query.Validate(); err != nil {
+		return err
+	}
+	if err = c.History.Validate(); err != nil {
+		return err
+	}
+	for name, object := range c.Objects {
+		if object == nil {
+			// object key without any setting (i.e. "sip:") use defaults
+			object = &ConfigObject{}
+			c.Objects[name] = object
+		}
+		if err = object.Validate(name); err != nil {
+			return err
+		}
+	}
+	if err = c.validateObjectNames(); err != nil {
+		return err
+	}
+	aggregations := make(map[string]bool)
+	for i := range c.Aggregation {
+		if err = c.Aggregation[i].Validate(); err != nil {
+			return err
+		}
+		key := fmt.Sprintf("%s/%s", c.Aggregation[i].Counter, c.Aggregation[i].Function)
+		if aggregations[key] || (c.Aggregation[i].ShareRatio && aggregations[c.Aggregation[i].Counter]) {
+			return fmt.Errorf("aggregation for counter %s is defined more times", c.Aggregation[i].Counter)
+		}
+		aggregations[key] = true
+		if c.Aggregation[i].ShareRatio {
+			aggregations[c.Aggregation[i].Counter] = true
+		}
+	}
+	if profile := productProfile(c.Product); len(profile.groupNames) == 0 {
+		// busy hour and aggregation are computed from standard counters
+		if c.BusyHour.Enabled {
+			return fmt.Errorf("busy hour isn't supported for product %s", c.Product)
+		}
+		if len(c.Aggregation) > 0 {
+			return fmt.Errorf("aggregation isn't supported for product %s", c.Product)
+		}
+	}
+	return nil
+}
+
+// validateObjectNames check that renamed object metrics don't collide with other exported metric names
+func (c *Config) validateObjectNames() error {
+	used := make(map[string]string)
+	for _, counter := range SupportedCounters {
+		used[counter.prometheusName] = counter.allowedCounterName
+	}
+	for _, object := range SupportedObjects {
+		cfg := c.Objects[object.configName]
+		for _, counter := range object.counters {
+			if cfg != nil {
+				if _, ok := cfg.Names[counter.allowedCounterName]; ok {
+					continue
+				}
+			}
+			used[counter.prometheusName] = objectKey(object.groupName, counter.allowedCounterName)
+		}
+	}
+	for _, object := range SupportedObjects {
+		cfg := c.Objects[object.configName]
+		if cfg == nil {
+			continue
+		}
+		for counter, metric := range cfg.Names {
+			if other, ok := used[metric]; ok {
+				return fmt.Errorf("name %s of counter %s in object %s is already used by %s", metric, counter, object.configName, other)
+			}
+			used[metric] = objectKey(object.groupName, counter)
+		}
+	}
+	return nil
+}
+
+// requestsPerMinute number of API requests per minute used by regular collection of session data
+func (c *Config) requestsPerMinute() int {
+	interval := int(c.PollInterval() / time.Second)
+	return (60 + interval - 1) / interval
+}
+
+// reservedRequests number of requests per minute reserved for other operations than collection
+//   - open session with add counters for every node and fixed reserve for query and descriptions
+//   - instance refresh do at most one step (list instances, add and remove counters) per minute
+func (c *Config) reservedRequests() int {
+	reserved := RateReservedRequests + 1 + len(c.MonitorNames)
+	if c.InstanceRefresh > 0 {
+		reserved += RateInstanceRefreshRequests
+	}
+	return reserved
+}
+
+// validateRateBudget check if regular collection and reserved requests fit in API rate limit
+func (c *Config) validateRateBudget() error {
+	collection, reserved := c.requestsPerMinute(), c.reservedRequests()
+	if collection+reserved > RateRequestLimit {
+		return fmt.Errorf("collection every %s needs %d requests per minute and %d are reserved for open session and other requests, API limit is %d requests per minute",
+			c.PollInterval(), collection, reserved, RateRequestLimit)
+	}
 	return nil
 }
 
+// PollInterval time between two collections of session data
+func (c *Config) PollInterval() time.Duration {
+	if c.Sampling.Enabled {
+		return time.Second * time.Duration(c.Sampling.Interval)
+	}
+	return time.Second * time.Duration(c.SleepBetweenRequest)
+}
+
 func (m *MetricsEnabled) Validate() bool {
 	return true
 }
@@ -337,7 +553,29 @@ func (c *Config) print() string {
 	a = fmt.Sprintf("%sTimeout:              [%d]\r\n", a, c.ApiTimeout)
 	a = fmt.Sprintf("%sSleep time:           [%d]\r\n", a, c.SleepBetweenRequest)
 	a = fmt.Sprintf("%sAllow stop:           [%t]\r\n", a, c.AllowStop)
+	a = fmt.Sprintf("%sInstance refresh:     [%d min]\r\n", a, c.InstanceRefresh)
+	if profile := productProfile(c.Product); profile != nil {
+		a = fmt.Sprintf("%s%s", a, profile.Print())
+	}
 
+	a = fmt.Sprintf("%s%s", a, c.Sampling.Print())
+	a = fmt.Sprintf("%s%s", a, c.BusyHour.Print())
+	a = fmt.Sprintf("%s%s", a, c.Query.Print())
+	a = fmt.Sprintf("%s%s", a, c.History.Print())
+	if len(c.Objects) > 0 {
+		a = fmt.Sprintf("%sObjects\r\n", a)
+		for _, object := range SupportedObjects {
+			if cfg, ok := c.Objects[object.configName]; ok {
+				a = fmt.Sprintf("%s%s", a, cfg.Print(object.configName))
+			}
+		}
+	}
+	if len(c.Aggregation) > 0 {
+		a = fmt.Sprintf("%sCluster aggregation\r\n", a)
+		for _, rule := range c.Aggregation {
+			a = fmt.Sprintf("%s%s", a, rule.Print())
+		}
+	}
 	a = fmt.Sprintf("%s%s", a, c.Metrics.Print())
 	a = fmt.Sprintf("%s%s", a, c.Log.Print())
 	return a
@@ -549,6 +787,36 @@ func (m *MetricsEnabled) enablePrometheusCounter(name string) bool {
 	if name == SWConferenceResourceTotal {
 		return m.SWConferenceResourceTotal
 	}
+	if name == BRIChannelsActive {
+		return m.BRIChannelsActive
+	}
+	if name == BRISpansInService {
+		return m.BRISpansInService
+	}
+	if name == FXOPortsActive {
+		return m.FXOPortsActive
+	}
+	if name == FXOPortsInService {
+		return m.FXOPortsInService
+	}
+	if name == FXSPortsActive {
+		return m.FXSPortsActive
+	}
+	if name == FXSPortsInService {
+		return m.FXSPortsInService
+	}
+	if name == PRIChannelsActive {
+		return m.PRIChannelsActive
+	}
+	if name == PRISpansInService {
+		return m.PRISpansInService
+	}
+	if name == T1ChannelsActive {
+		return m.T1ChannelsActive
+	}
+	if name == T1SpansInService {
+		return m.T1SpansInService
+	}
 	if name == RegisteredAnalogAccess {
 		return m.RegisteredAnalogAccess
 	}
@@ -614,6 +882,165 @@ func (a *ConfigLog) Print() string {
 	return o
 }
 
+func (a *ConfigSampling) Validate() (err error) {
+	if !a.Enabled {
+		return nil
+	}
+	if !SamplingIntervalLimit.Validate(a.Interval) {
+		return errors.New("defined sampling interval is not valid")
+	}
+	if !SamplingWindowLimit.Validate(a.Window) || a.Window < a.Interval {
+		return errors.New("defined sampling window is not valid, it must be longer than sampling interval")
+	}
+	return nil
+}
+
+func (a *ConfigSampling) Print() string {
+	o := "Sampling\r\n"
+	o = fmt.Sprintf("%s\t- Enabled                   [%t]\r\n", o, a.Enabled)
+	if a.Enabled {
+		o = fmt.Sprintf("%s\t- Interval                  [%d]\r\n", o, a.Interval)
+		o = fmt.Sprintf("%s\t- Window                    [%d]\r\n", o, a.Window)
+	}
+	return o
+}
+
+func (a *ConfigBusyHour) Validate() (err error) {
+	a.StateFile = FixFileName(a.StateFile)
+	if len(a.StateFile) == 0 {
+		a.StateFile = BusyHourDefaultStateFile
+	}
+	return nil
+}
+
+func (a *ConfigBusyHour) Print() string {
+	o := "Busy hour\r\n"
+	o = fmt.Sprintf("%s\t- Enabled                   [%t]\r\n", o, a.Enabled)
+	if a.Enabled {
+		o = fmt.Sprintf("%s\t- State file                [%s]\r\n", o, a.StateFile)
+	}
+	return o
+}
+
+func (a *ConfigQuery) Validate() (err error) {
+	if a.Enabled && (len(a.User) < 1 || len(a.Password) < 1) {
+		return errors.New("query endpoint require user and password")
+	}
+	return nil
+}
+
+func (a *ConfigQuery) Print() string {
+	o := "Query endpoint\r\n"
+	o = fmt.Sprintf("%s\t- Enabled                   [%t]\r\n", o, a.Enabled)
+	if a.Enabled {
+		o = fmt.Sprintf("%s\t- User                      [%s]\r\n", o, a.User)
+	}
+	return o
+}
+
+func (a *ConfigHistory) Validate() (err error) {
+	a.Directory = FixFileName(a.Directory)
+	if len(a.Directory) == 0 {
+		a.Directory = HistoryDefaultDirectory
+	}
+	if a.Enabled && !HistoryRetentionLimit.Validate(a.RetentionDays) {
+		return errors.New("defined history retention days is not valid")
+	}
+	return nil
+}
+
+func (a *ConfigHistory) Print() string {
+	o := "History\r\n"
+	o = fmt.Sprintf("%s\t- Enabled                   [%t]\r\n", o, a.Enabled)
+	if a.Enabled {
+		o = fmt.Sprintf("%s\t- Directory                 [%s]\r\n", o, a.Directory)
+		o = fmt.Sprintf("%s\t- Retention in days         [%d]\r\n", o, a.RetentionDays)
+	}
+	return o
+}
+
+func (a *AggregationRule) Validate() (err error) {
+	if supportedCounter(a.Counter) == nil {
+		return fmt.Errorf("aggregation counter %s isn't supported", a.Counter)
+	}
+	a.Function = strings.ToLower(a.Function)
+	if len(a.Function) == 0 {
+		a.Function = AggregationSum
+	}
+	if !inSlice(a.Function, AggregationFunctions) {
+		return fmt.Errorf("aggregation function %s for counter %s isn't valid", a.Function, a.Counter)
+	}
+	return nil
+}
+
+func (a *AggregationRule) Print() string {
+	return fmt.Sprintf("\t- %s [%s] share ratio [%t]\r\n", a.Counter, a.Function, a.ShareRatio)
+}
+
+func (o *ConfigObject) Validate(name string) (err error) {
+	var object *ObjectCounters
+	for i := range SupportedObjects {
+		if SupportedObjects[i].configName == name {
+			object = &SupportedObjects[i]
+		}
+	}
+	if object == nil {
+		return fmt.Errorf("object %s isn't supported", name)
+	}
+	for counter := range o.Counters {
+		if object.counter(counter) == nil {
+			return fmt.Errorf("counter %s isn't supported for object %s", counter, name)
+		}
+	}
+	for counter, metric := range o.Names {
+		c := object.counter(counter)
+		if c == nil {
+			return fmt.Errorf("counter %s isn't supported for object %s", counter, name)
+		}
+		if c.channel > 0 {
+			return fmt.Errorf("counter %s of object %s is channel status and can't be renamed", counter, name)
+		}
+		if !metricNameRegex.MatchString(metric) {
+			return fmt.Errorf("name %s of counter %s in object %s isn't valid Prometheus metric name", metric, counter, name)
+		}
+	}
+	o.include = nil
+	if len(o.Include) > 0 {
+		if o.include, err = regexp.Compile(o.Include); err != nil {
+			return fmt.Errorf("include for object %s isn't valid regular expression. Error: %s", name, err)
+		}
+	}
+	o.exclude = nil
+	if len(o.Exclude) > 0 {
+		if o.exclude, err = regexp.Compile(o.Exclude); err != nil {
+			return fmt.Errorf("exclude for object %s isn't valid regular expression. Error: %s", name, err)
+		}
+	}
+	return nil
+}
+
+func (o *ConfigObject) instanceAllowed(instance string) bool {
+	if o.include != nil && !o.include.MatchString(instance) {
+		return false
+	}
+	return o.exclude == nil || !o.exclude.MatchString(instance)
+}
+
+func (o *ConfigObject) Print(name string) string {
+	a := fmt.Sprintf("\t- %s [%t]", name, o.Enabled)
+	if len(o.Include) > 0 {
+		a = fmt.Sprintf("%s include [%s]", a, o.Include)
+	}
+	if len(o.Exclude) > 0 {
+		a = fmt.Sprintf("%s exclude [%s]", a, o.Exclude)
+	}
+	a += "\r\n"
+	for counter, metric := range o.Names {
+		a = fmt.Sprintf("%s\t\t- %s as [%s]\r\n", a, counter, metric)
+	}
+	return a
+}
+
 func (a *ConfigLog) LogToFile() bool {
 	return len(a.FileName) > 0
 }
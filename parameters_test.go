@@ -0,0 +1,80 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestConfigObjectNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		names   map[string]string
+		wantErr bool
+	}{
+		{"valid rename", map[string]string{MembersAvailable: "helpdesk_agents_available"}, false},
+		{"unknown counter", map[string]string{"Unknown": "helpdesk_unknown"}, true},
+		{"invalid metric name", map[string]string{MembersAvailable: "helpdesk-agents"}, true},
+		{"name of other metric", map[string]string{MembersAvailable: "cucm_hunt_list_calls_active"}, true},
+		{"same name for two counters", map[string]string{MembersAvailable: "helpdesk", CallsActive: "helpdesk"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Config{Objects: map[string]*ConfigObject{"huntLists": {Enabled: true, Names: tt.names}}}
+			err := c.Objects["huntLists"].Validate("huntLists")
+			if err == nil {
+				err = c.validateObjectNames()
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validate names error %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestObjectMetricName(t *testing.T) {
+	saved := config.Objects
+	defer func() { config.Objects = saved }()
+	config.Objects = map[string]*ConfigObject{"huntLists": {Enabled: true, Names: map[string]string{MembersAvailable: "helpdesk_agents_available"}}}
+	object := supportedObject("Cisco Hunt Lists")
+	if name := object.metricName(object.counter(MembersAvailable)); name != "helpdesk_agents_available" {
+		t.Errorf("renamed counter has name %s", name)
+	}
+	if name := object.metricName(object.counter(CallsActive)); name != "cucm_hunt_list_calls_active" {
+		t.Errorf("not renamed counter has name %s", name)
+	}
+}
+
+func TestConfigEmptyObject(t *testing.T) {
+	c := *config
+	c.Objects = map[string]*ConfigObject{}
+	content := "apiAddress: cucm.example.com\napiUser: user\napiPwd: pwd\nmonitor_names:\n  - node1\nobjects:\n  sip:\n"
+	if err := c.ProcessLoadFile([]byte(content)); err != nil {
+		t.Fatalf("config with empty object error %v", err)
+	}
+	if object := c.Objects["sip"]; object == nil || object.Enabled {
+		t.Errorf("empty object is %+v, want disabled object with defaults", object)
+	}
+}
+
+func TestConfigProductFeatures(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{"busy hour for cucm", "busyHour:\n  enabled: true\n", false},
+		{"busy hour for cuc", "product: cuc\nbusyHour:\n  enabled: true\n", true},
+		{"aggregation for cucm", "aggregation:\n  - counter: CallsActive\n", false},
+		{"aggregation for imp", "product: imp\naggregation:\n  - counter: CallsActive\n", true},
+		{"cuc without computed metrics", "product: cuc\n", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := *config
+			c.Objects = map[string]*ConfigObject{}
+			content := "apiAddress: cucm.example.com\napiUser: user\napiPwd: pwd\nmonitor_names:\n  - node1\n" + tt.content
+			if err := c.ProcessLoadFile([]byte(content)); (err != nil) != tt.wantErr {
+				t.Errorf("config error %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
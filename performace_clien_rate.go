@@ -6,10 +6,12 @@ import (
 )
 
 const (
-	RateStandardDelay     = time.Second + time.Millisecond*200
-	RateStandardTestDelay = RateStandardDelay + time.Millisecond*300
-	RateBaseWaitTime      = time.Minute + time.Millisecond*200
-	RateRequestLimit      = 50
+	RateStandardDelay           = time.Second + time.Millisecond*200
+	RateStandardTestDelay       = RateStandardDelay + time.Millisecond*300
+	RateBaseWaitTime            = time.Minute + time.Millisecond*200
+	RateRequestLimit            = 50
+	RateReservedRequests        = 10 // requests per minute reserved for query endpoint, descriptions and retries
+	RateInstanceRefreshRequests = 3  // requests per minute used by one step of instance refresh
 )
 
 type RateControl struct {
@@ -48,3 +50,10 @@ func (r *RateControl) delay() time.Duration {
 	r.reset()
 	return waitTime
 }
+
+// state actual state of rate control for status pages
+func (r *RateControl) state() RateState {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return RateState{Requests: r.requests, Limit: RateRequestLimit, WindowStart: r.start}
+}
@@ -3,10 +3,11 @@ package main
 import (
 	"context"
 	"crypto/tls"
-	"encoding/xml"
 	"fmt"
 	"net/http"
 	"net/http/cookiejar"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -14,16 +15,18 @@ import (
 
 // ApiMonitorClient API client
 type ApiMonitorClient struct {
-	client         *http.Client // client reference to exist HTTP Client
-	session        string       // session actual id
-	requests       uint64       // requests success created request
-	responses      uint64       // responses success obtains response
-	responseErrors uint64       // responseErrors error obtain response
+	client         *http.Client  // client reference to exist HTTP Client
+	session        string        // session actual id, use sessionId and setSession
+	sessionMutex   sync.RWMutex  // sessionMutex guard session, mutex is held during rate wait
+	requests       atomic.Uint64 // requests success created request
+	responses      atomic.Uint64 // responses success obtains response
+	responseErrors atomic.Uint64 // responseErrors error obtain response
+	mutex          sync.Mutex    // mutex serialize requests from monitoring and web server
 }
 
 // NewApiMonitorClient create new API client with prepared http.Client
 func NewApiMonitorClient() *ApiMonitorClient {
-	var cp ApiMonitorClient
+	var cp *ApiMonitorClient
 
 	jar, _ := cookiejar.New(nil)
 
@@ -31,85 +34,102 @@ func NewApiMonitorClient() *ApiMonitorClient {
 		tr := &http.Transport{
 			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
 		}
-		cp = ApiMonitorClient{
-			client:         &http.Client{Transport: tr, Jar: jar},
-			requests:       0,
-			responses:      0,
-			responseErrors: 0,
-			session:        "",
+		cp = &ApiMonitorClient{
+			client:  &http.Client{Transport: tr, Jar: jar},
+			session: "",
 		}
 	} else {
-		cp = ApiMonitorClient{
-			client:         &http.Client{Jar: jar},
-			requests:       0,
-			responses:      0,
-			responseErrors: 0,
-			session:        "",
+		cp = &ApiMonitorClient{
+			client:  &http.Client{Jar: jar},
+			session: "",
 		}
 	}
-	return &cp
+	return cp
 }
 
 // processRequest process one request to API with predefined timeout
-// program returns collected body or error if here any problem
-func (p *ApiMonitorClient) processRequest(name string, inner string) (body string, err error) {
+// response is decoded directly from stream into data (nil data skip response content)
+// program returns *ApiError if here any problem
+func (p *ApiMonitorClient) processRequest(name string, inner string, data interface{}) (err error) {
 	if LogRequestDuration {
 		defer duration(track(log.Fields{FieldRoutine: "processRequest"}, "procedure ends"))
 	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
 	var req *http.Request
 	var resp *http.Response
 	s := fmt.Sprintf(Envelope, inner)
 	requestId := RandomString()
 	req, err = perfRequestCreate(requestId, s)
-	p.requests++
+	p.requests.Add(1)
 	if err != nil {
 		log.WithFields(p.logFields(name)).Errorf("problem prepare %s request. Error: %s", name, err)
-		return "", err
+		return err
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(config.ApiTimeout)*time.Second)
 	defer cancel()
 	req = req.WithContext(ctx)
 
-	body, resp, err = perfRequestResponse(requestId, p.client, req)
-	if resp != nil && resp.StatusCode > 299 {
-		log.WithFields(p.logFields(name)).Errorf("problem read %s response. Status code: %s", name, resp.Status)
-		var f FaultResponse
-		err = xml.Unmarshal([]byte(body), &f)
-		p.responseErrors++
-		if resp.StatusCode == 401 || err != nil {
-			return fmt.Sprintf("%d", resp.StatusCode), fmt.Errorf("response status is %s - %s %s", resp.Status, f.FaultCode, f.FaultString)
-		}
-		return f.FaultCode, fmt.Errorf("response status is %s - %s %s", resp.Status, f.FaultCode, f.FaultString)
+	resp, err = perfRequestResponse(requestId, p.client, req)
+	if err != nil {
+		return p.apiError(newApiError(transportErrorKind(err), name, resp, nil, err))
 	}
+	defer func() { _ = resp.Body.Close() }()
 
+	var fault *FaultResponse
+	if resp.StatusCode != http.StatusUnauthorized && resp.StatusCode != http.StatusForbidden {
+		fault, err = perfDecodeResponse(resp.Body, data)
+	}
+	if resp.StatusCode > 299 || fault != nil {
+		return p.apiError(newApiError(responseErrorKind(resp, fault), name, resp, fault, nil))
+	}
 	if err != nil {
-		log.WithFields(p.logFields(name)).Errorf("problem read %s response. Error: %s", name, err)
-		p.responseErrors++
-		return "", err
+		return p.apiError(newApiError(decodeErrorKind(err), name, resp, nil, err))
 	}
 
 	for _, cookie := range resp.Cookies() {
 		if cookie.Name == "JSESSIONIDSSO" {
-			p.session = cookie.Value
-			log.WithFields(p.logFields(name)).Debugf("JSESSIONIDSSO cookie received and stored: %s", p.session)
+			p.setSession(cookie.Value)
+			log.WithFields(p.logFields(name)).Debugf("JSESSIONIDSSO cookie received and stored: %s", cookie.Value)
 			break
 		}
 		log.WithFields(p.logFields(name)).Debugf("no cookie JSESSIONIDSSO")
 	}
 
-	p.responses++
-	body, err = perfRequestBodyRelevant(body)
-	if err != nil {
-		log.WithFields(p.logFields(name)).Errorf("problem analyze %s response. Error: %s", name, err)
-		return "", err
+	p.responses.Add(1)
+	return nil
+}
+
+// apiError log and count error from API request
+func (p *ApiMonitorClient) apiError(err *ApiError) error {
+	log.WithFields(p.logFields(err.Operation)).WithField(FieldErrorKind, string(err.Kind)).
+		Errorf("problem process %s request. Error: %s", err.Operation, err)
+	p.responseErrors.Add(1)
+	monitorStatus.addError(err.Operation, string(err.Kind), err.Error())
+	if apiErrorMetrics != nil {
+		apiErrorMetrics.WithLabelValues(string(err.Kind)).Inc()
 	}
-	return body, nil
+	return err
 }
 
 // isSessionOpen Define if connection is UP
 func (p *ApiMonitorClient) isSessionOpen() bool {
-	return len(p.session) > 0
+	return len(p.sessionId()) > 0
+}
+
+// sessionId actual session id, safe for web server handlers
+func (p *ApiMonitorClient) sessionId() string {
+	p.sessionMutex.RLock()
+	defer p.sessionMutex.RUnlock()
+	return p.session
+}
+
+// setSession store actual session id, empty id for closed session
+func (p *ApiMonitorClient) setSession(id string) {
+	p.sessionMutex.Lock()
+	defer p.sessionMutex.Unlock()
+	p.session = id
 }
 
 // logFields create valid list of log fields depend on server
@@ -117,14 +137,14 @@ func (p *ApiMonitorClient) logFields(operation ...string) log.Fields {
 	var f log.Fields
 	if len(operation) == 1 {
 		f = log.Fields{
-			FieldSession: p.session,
+			FieldSession: p.sessionId(),
 			FieldIsUp:    p.isSessionOpen(),
 			FieldRoutine: operation,
 		}
 	} else {
 		f = log.Fields{
 			FieldIsUp:    p.isSessionOpen(),
-			FieldSession: p.session,
+			FieldSession: p.sessionId(),
 		}
 	}
 	return f
@@ -133,9 +153,9 @@ func (p *ApiMonitorClient) logFields(operation ...string) log.Fields {
 // print List actual error status of client
 func (p *ApiMonitorClient) print() string {
 	msg := "Client status"
-	msg = fmt.Sprintf("%s\r\nRequests     %d", msg, p.requests)
-	msg = fmt.Sprintf("%s\r\nResponses    %d", msg, p.responses)
-	msg = fmt.Sprintf("%s\r\nError        %d", msg, p.responseErrors)
+	msg = fmt.Sprintf("%s\r\nRequests     %d", msg, p.requests.Load())
+	msg = fmt.Sprintf("%s\r\nResponses    %d", msg, p.responses.Load())
+	msg = fmt.Sprintf("%s\r\nError        %d", msg, p.responseErrors.Load())
 	msg = fmt.Sprintf("%s\r\nConnected    %t", msg, p.isSessionOpen())
 	return msg
 }
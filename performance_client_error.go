@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ApiErrorKind classify problem returned from PerfMon API processing
+type ApiErrorKind string
+
+const (
+	ErrAuthFailure       ApiErrorKind = "auth_failure"       // user not authorized for PerfMon API
+	ErrRateLimit         ApiErrorKind = "rate_limit"         // exceeded allowed rate of PerfMon requests
+	ErrInvalidSession    ApiErrorKind = "invalid_session"    // session handle not exists or expired
+	ErrInvalidCounter    ApiErrorKind = "invalid_counter"    // counter, object or instance not exists
+	ErrTimeout           ApiErrorKind = "timeout"            // request not finished in defined timeout
+	ErrTransport         ApiErrorKind = "transport_failure"  // network or TLS problem
+	ErrMalformedResponse ApiErrorKind = "malformed_response" // response can't be decoded or has unexpected status
+	ErrSoapFault         ApiErrorKind = "soap_fault"         // other SOAP fault returned by server
+)
+
+var apiErrorMessages = map[ApiErrorKind]string{
+	ErrAuthFailure:       "user not authorize for use performance API",
+	ErrRateLimit:         "exceeded allowed rate for Perfmon information",
+	ErrInvalidSession:    "invalid session handle",
+	ErrInvalidCounter:    "invalid counter",
+	ErrTimeout:           "request timeout",
+	ErrTransport:         "transport failure",
+	ErrMalformedResponse: "malformed response",
+	ErrSoapFault:         "SOAP fault",
+}
+
+// ApiErrorKinds list of all error kinds in stable order
+var ApiErrorKinds = []ApiErrorKind{ErrAuthFailure, ErrRateLimit, ErrInvalidSession, ErrInvalidCounter,
+	ErrTimeout, ErrTransport, ErrMalformedResponse, ErrSoapFault}
+
+// Error message for error kind
+func (k ApiErrorKind) Error() string {
+	return apiErrorMessages[k]
+}
+
+// ApiError error returned from PerfMon API request
+type ApiError struct {
+	Kind      ApiErrorKind   // Kind classification of error
+	Operation string         // Operation name of API request
+	Status    string         // Status HTTP status, empty when response not received
+	Fault     *FaultResponse // Fault SOAP fault when server returns it
+	Err       error          // Err underlying error
+}
+
+// Error message contains kind, operation and available details
+func (e *ApiError) Error() string {
+	msg := fmt.Sprintf("%s: %s", e.Operation, e.Kind.Error())
+	if len(e.Status) > 0 {
+		msg = fmt.Sprintf("%s - response status is %s", msg, e.Status)
+	}
+	if e.Fault != nil {
+		msg = fmt.Sprintf("%s - %s %s", msg, e.Fault.FaultCode, e.Fault.FaultString)
+	}
+	if e.Err != nil {
+		msg = fmt.Sprintf("%s - %s", msg, e.Err)
+	}
+	return msg
+}
+
+// Unwrap allow errors.Is for error kind and underlying error
+func (e *ApiError) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Kind}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+// newApiError create error of required kind
+func newApiError(kind ApiErrorKind, operation string, resp *http.Response, fault *FaultResponse, err error) *ApiError {
+	e := &ApiError{Kind: kind, Operation: operation, Fault: fault, Err: err}
+	if resp != nil {
+		e.Status = resp.Status
+	}
+	return e
+}
+
+// transportErrorKind classify error from HTTP client
+func transportErrorKind(err error) ApiErrorKind {
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return ErrTimeout
+	}
+	return ErrTransport
+}
+
+// decodeErrorKind classify error from decoding of response body, timeout during read of body isn't malformed response
+func decodeErrorKind(err error) ApiErrorKind {
+	if transportErrorKind(err) == ErrTimeout {
+		return ErrTimeout
+	}
+	return ErrMalformedResponse
+}
+
+// faultErrorKind classify SOAP 1.1 fault returned from PerfMon API
+func faultErrorKind(fault *FaultResponse) ApiErrorKind {
+	text := strings.ToLower(fmt.Sprintf("%s %s %s", fault.FaultCode, fault.FaultString, fault.Detail.Content))
+	switch {
+	case strings.Contains(text, "ratecontrol") || strings.Contains(text, "exceeded allowed rate"):
+		return ErrRateLimit
+	case strings.Contains(text, "session handle") || strings.Contains(text, "sessionhandle"):
+		return ErrInvalidSession
+	case strings.Contains(text, "authenticat") || strings.Contains(text, "authoriz"):
+		return ErrAuthFailure
+	case strings.Contains(text, "counter") || strings.Contains(text, "object") || strings.Contains(text, "instance"):
+		return ErrInvalidCounter
+	}
+	return ErrSoapFault
+}
+
+// responseErrorKind classify API response based on HTTP status and SOAP fault
+func responseErrorKind(resp *http.Response, fault *FaultResponse) ApiErrorKind {
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return ErrAuthFailure
+	}
+	if fault != nil {
+		return faultErrorKind(fault)
+	}
+	return ErrMalformedResponse
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestFaultErrorKind(t *testing.T) {
+	tests := []struct {
+		name   string
+		fault  FaultResponse
+		detail string
+		expect ApiErrorKind
+	}{
+		{"rate control detail", FaultResponse{FaultCode: "soapenv:Server", FaultString: "Exceeded"}, "<ns1:RateControl>limit</ns1:RateControl>", ErrRateLimit},
+		{"rate message", FaultResponse{FaultString: "Exceeded allowed rate for Perfmon information"}, "", ErrRateLimit},
+		{"invalid session", FaultResponse{FaultString: "Invalid Session Handle"}, "", ErrInvalidSession},
+		{"session handle element", FaultResponse{FaultString: "SessionHandle not found"}, "", ErrInvalidSession},
+		{"unknown counter", FaultResponse{FaultString: "Counter not found: \\\\node\\Cisco CallManager\\Unknown"}, "", ErrInvalidCounter},
+		{"unknown object", FaultResponse{FaultString: "Object Cisco Unknown does not exist"}, "", ErrInvalidCounter},
+		{"unknown instance", FaultResponse{FaultString: "Instance trunk01 not found"}, "", ErrInvalidCounter},
+		{"authentication", FaultResponse{FaultString: "Authentication failed"}, "", ErrAuthFailure},
+		{"authorization", FaultResponse{FaultString: "User is not authorized"}, "", ErrAuthFailure},
+		{"authorization for object", FaultResponse{FaultString: "User is not authorized to read object Cisco CallManager"}, "", ErrAuthFailure},
+		{"other fault", FaultResponse{FaultCode: "soapenv:Server", FaultString: "java.lang.NullPointerException"}, "", ErrSoapFault},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fault.Detail.Content = tt.detail
+			if kind := faultErrorKind(&tt.fault); kind != tt.expect {
+				t.Errorf("faultErrorKind() = %s, want %s", kind, tt.expect)
+			}
+		})
+	}
+}
+
+func TestResponseErrorKind(t *testing.T) {
+	fault := &FaultResponse{FaultString: "Invalid Session Handle"}
+	tests := []struct {
+		name   string
+		status int
+		fault  *FaultResponse
+		expect ApiErrorKind
+	}{
+		{"unauthorized", http.StatusUnauthorized, nil, ErrAuthFailure},
+		{"forbidden with fault", http.StatusForbidden, fault, ErrAuthFailure},
+		{"server error with fault", http.StatusInternalServerError, fault, ErrInvalidSession},
+		{"server error without fault", http.StatusServiceUnavailable, nil, ErrMalformedResponse},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if kind := responseErrorKind(&http.Response{StatusCode: tt.status}, tt.fault); kind != tt.expect {
+				t.Errorf("responseErrorKind() = %s, want %s", kind, tt.expect)
+			}
+		})
+	}
+}
+
+func TestTransportErrorKind(t *testing.T) {
+	if kind := transportErrorKind(fmt.Errorf("request: %w", context.DeadlineExceeded)); kind != ErrTimeout {
+		t.Errorf("deadline exceeded classified as %s", kind)
+	}
+	if kind := transportErrorKind(errors.New("connection refused")); kind != ErrTransport {
+		t.Errorf("connection refused classified as %s", kind)
+	}
+}
+
+func TestDecodeErrorKind(t *testing.T) {
+	if kind := decodeErrorKind(fmt.Errorf("read body: %w", context.DeadlineExceeded)); kind != ErrTimeout {
+		t.Errorf("deadline during read of body is %s, want %s", kind, ErrTimeout)
+	}
+	if kind := decodeErrorKind(errors.New("XML syntax error on line 1")); kind != ErrMalformedResponse {
+		t.Errorf("XML syntax error is %s, want %s", kind, ErrMalformedResponse)
+	}
+}
+
+func TestApiErrorIs(t *testing.T) {
+	cause := errors.New("x509: certificate signed by unknown authority")
+	err := error(newApiError(ErrTransport, "OpenSession", nil, nil, cause))
+	if !errors.Is(err, ErrTransport) || !errors.Is(err, cause) {
+		t.Errorf("errors.Is doesn't match kind or cause of %s", err)
+	}
+	if errors.Is(err, ErrTimeout) {
+		t.Errorf("errors.Is match other kind")
+	}
+}
@@ -1,23 +1,27 @@
 package main
 
 import (
-	"bytes"
 	"encoding/xml"
 	"errors"
 	"fmt"
 	log "github.com/sirupsen/logrus"
 	"io"
 	"net/http"
-	"regexp"
+	"strings"
 	"time"
 )
 
+const (
+	SoapEnvelopeNamespace = "http://schemas.xmlsoap.org/soap/envelope/" // SOAP 1.1 envelope namespace
+)
+
 type FaultResponse struct {
 	XMLName     xml.Name `xml:"Fault"`
-	Text        string   `xml:",chardata"`
 	FaultCode   string   `xml:"faultcode"`
 	FaultString string   `xml:"faultstring"`
-	Detail      string   `xml:"detail"`
+	Detail      struct {
+		Content string `xml:",innerxml"`
+	} `xml:"detail"`
 }
 
 // perfRequestCreate generate http request wit request ID and body
@@ -29,7 +33,7 @@ func perfRequestCreate(requestId string, body string) (req *http.Request, err er
 	}
 	server := fmt.Sprintf("https://%s:8443/perfmonservice2/services/PerfmonService?wsdl", config.ApiAddress)
 	log.WithFields(log.Fields{FieldRoutine: "perfRequestCreate", FieldRequestId: requestId}).Tracef("prepare server API name: %s", server)
-	req, err = http.NewRequest("POST", server, bytes.NewBuffer([]byte(body)))
+	req, err = http.NewRequest("POST", server, strings.NewReader(body))
 	if err != nil {
 		log.WithField(FieldRoutine, "perfRequestCreate").Errorf("problem create request. Error: %s", err)
 		return nil, err
@@ -43,7 +47,9 @@ func perfRequestCreate(requestId string, body string) (req *http.Request, err er
 	return req, nil
 }
 
-func perfRequestResponse(requestId string, client *http.Client, req *http.Request) (body string, resp *http.Response, err error) {
+// perfRequestResponse send request to API server respecting rate limits
+//   - response body is not read, caller is responsible for decode and close it
+func perfRequestResponse(requestId string, client *http.Client, req *http.Request) (resp *http.Response, err error) {
 	log.WithFields(log.Fields{FieldRoutine: "perfRequestResponse", FieldRequestId: requestId}).Trace("get response")
 	if LogRequestDuration {
 		defer duration(track(log.Fields{FieldRoutine: "perfRequestResponse", FieldRequestId: requestId}, "procedure ends"))
@@ -63,22 +69,44 @@ func perfRequestResponse(requestId string, client *http.Client, req *http.Reques
 	resp, err = client.Do(req)
 	if err != nil {
 		log.WithFields(log.Fields{FieldRoutine: "perfRequestResponse", FieldRequestId: requestId}).Errorf("problem process request. Error: %s", err)
-		return "", resp, err
+		return resp, err
 	}
-	s, err := io.ReadAll(resp.Body)
-	return string(s), resp, err
+	return resp, nil
 }
 
-func perfRequestBodyRelevant(body string) (data string, err error) {
-	var rex = regexp.MustCompile(`(?m)<soapenv:Body>((.|\n)*?)</soapenv:Body>`)
-
-	if !rex.Match([]byte(body)) {
-		return "", errors.New("response body not contains \"<soapenv:Body>\"")
-	}
-	x := rex.FindStringSubmatch(body)
-	if len(x) < 2 {
-		return "", errors.New("response body not contains \"<soapenv:Body>\"")
+// perfDecodeResponse decode SOAP envelope directly from response stream in one pass
+//   - when body contains SOAP fault it is decoded and returned as fault
+//   - otherwise first element inside body is decoded into data, nil data only skip it
+func perfDecodeResponse(r io.Reader, data interface{}) (fault *FaultResponse, err error) {
+	decoder := xml.NewDecoder(r)
+	inBody := false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil, errors.New("response body not contains \"<soapenv:Body>\"")
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			if !inBody {
+				inBody = t.Name.Space == SoapEnvelopeNamespace && t.Name.Local == "Body"
+				continue
+			}
+			if t.Name.Space == SoapEnvelopeNamespace && t.Name.Local == "Fault" {
+				fault = &FaultResponse{}
+				return fault, decoder.DecodeElement(fault, &t)
+			}
+			if data == nil {
+				return nil, decoder.Skip()
+			}
+			return nil, decoder.DecodeElement(data, &t)
+		case xml.EndElement:
+			if inBody {
+				// empty body
+				return nil, nil
+			}
+		}
 	}
-	data = x[1]
-	return data, nil
 }
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// listCounterFixture SOAP response of perfmonListCounter with objects * counters items
+func listCounterFixture(objects int, counters int) []byte {
+	var b bytes.Buffer
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?><soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/" ` +
+		`xmlns:xsd="http://www.w3.org/2001/XMLSchema" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"><soapenv:Body>` +
+		`<ns1:perfmonListCounterResponse xmlns:ns1="http://schemas.cisco.com/ast/soap">`)
+	for o := 0; o < objects; o++ {
+		fmt.Fprintf(&b, "<ns1:perfmonListCounterReturn><ns1:Name>Cisco Object %d</ns1:Name><ns1:MultiInstance>%t</ns1:MultiInstance><ns1:ArrayOfCounter>", o, o%2 == 0)
+		for c := 0; c < counters; c++ {
+			fmt.Fprintf(&b, "<ns1:item><ns1:Name>Counter Name Number %d</ns1:Name></ns1:item>", c)
+		}
+		b.WriteString("</ns1:ArrayOfCounter></ns1:perfmonListCounterReturn>")
+	}
+	b.WriteString("</ns1:perfmonListCounterResponse></soapenv:Body></soapenv:Envelope>")
+	return b.Bytes()
+}
+
+// legacyDecodeResponse decode response as before streaming decoder, read whole body, cut body by regex and unmarshal it
+func legacyDecodeResponse(r io.Reader, data interface{}) error {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	var rex = regexp.MustCompile(`(?m)<soapenv:Body>((.|\n)*?)</soapenv:Body>`)
+	x := rex.FindStringSubmatch(string(body))
+	if len(x) < 2 {
+		return errors.New("response body not contains \"<soapenv:Body>\"")
+	}
+	return xml.Unmarshal([]byte(x[1]), data)
+}
+
+func TestPerfDecodeResponse(t *testing.T) {
+	var list XmlListCounterResponse
+	fault, err := perfDecodeResponse(bytes.NewReader(listCounterFixture(3, 4)), &list)
+	if err != nil || fault != nil {
+		t.Fatalf("unexpected error %v, fault %v", err, fault)
+	}
+	if len(list.ListCounterReturn) != 3 || len(list.ListCounterReturn[2].ArrayOfCounter.Item) != 4 {
+		t.Fatalf("unexpected decoded list %+v", list)
+	}
+	if list.ListCounterReturn[1].Name != "Cisco Object 1" || list.ListCounterReturn[1].MultiInstance {
+		t.Errorf("unexpected object %+v", list.ListCounterReturn[1])
+	}
+
+	var legacy XmlListCounterResponse
+	if err = legacyDecodeResponse(bytes.NewReader(listCounterFixture(3, 4)), &legacy); err != nil {
+		t.Fatalf("legacy decode error %v", err)
+	}
+	if fmt.Sprint(legacy.ListCounterReturn) != fmt.Sprint(list.ListCounterReturn) {
+		t.Errorf("streaming and legacy decoder differ")
+	}
+}
+
+func TestPerfDecodeResponseFault(t *testing.T) {
+	body := `<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/"><soapenv:Body><soapenv:Fault>` +
+		`<faultcode>soapenv:Server</faultcode><faultstring>Exceeded allowed rate for Perfmon information</faultstring>` +
+		`<detail><ns1:RateControl xmlns:ns1="x">limit</ns1:RateControl></detail></soapenv:Fault></soapenv:Body></soapenv:Envelope>`
+	var list XmlListCounterResponse
+	fault, err := perfDecodeResponse(strings.NewReader(body), &list)
+	if err != nil || fault == nil {
+		t.Fatalf("expected fault, error %v", err)
+	}
+	if fault.FaultCode != "soapenv:Server" || !strings.Contains(fault.Detail.Content, "RateControl") {
+		t.Errorf("unexpected fault %+v", fault)
+	}
+}
+
+func TestPerfDecodeResponseInvalid(t *testing.T) {
+	tests := map[string]struct {
+		body    string
+		wantErr bool
+	}{
+		"empty body": {body: `<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/"><soapenv:Body></soapenv:Body></soapenv:Envelope>`},
+		"no body":    {body: `<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/"></soapenv:Envelope>`, wantErr: true},
+		"html":       {body: `<html><body>Service Unavailable</body></html>`, wantErr: true},
+		"truncated":  {body: `<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/"><soapenv:Body><x>`, wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var list XmlListCounterResponse
+			fault, err := perfDecodeResponse(strings.NewReader(tt.body), &list)
+			if fault != nil {
+				t.Errorf("unexpected fault %+v", fault)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// benchmarkFixture about 4 MB list counter response, size of response from large cluster node
+var benchmarkFixture = listCounterFixture(400, 150)
+
+func BenchmarkDecodeLegacy(b *testing.B) {
+	b.ReportAllocs()
+	b.SetBytes(int64(len(benchmarkFixture)))
+	for i := 0; i < b.N; i++ {
+		var list XmlListCounterResponse
+		if err := legacyDecodeResponse(bytes.NewReader(benchmarkFixture), &list); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeStreaming(b *testing.B) {
+	b.ReportAllocs()
+	b.SetBytes(int64(len(benchmarkFixture)))
+	for i := 0; i < b.N; i++ {
+		var list XmlListCounterResponse
+		if _, err := perfDecodeResponse(bytes.NewReader(benchmarkFixture), &list); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -6,19 +6,32 @@ import (
 	"fmt"
 	log "github.com/sirupsen/logrus"
 	"strings"
+	"time"
 )
 
 // PerfMonService struct hold CUCM API and list of CUCM cluster server names
 type PerfMonService struct {
 	monitors []ClusterHostMonitorData // monitors list of CUCM cluster server names
 	client   *ApiMonitorClient        // client API client with prepared http.Client
+	refresh  instanceRefresh          // refresh state of periodic refresh of multi instance objects
+}
+
+// instanceRefresh state of periodic refresh of multi instance objects, one group of one server is refreshed per step
+type instanceRefresh struct {
+	next  time.Time      // next start of refresh of all groups
+	step  time.Time      // step time of last refresh step
+	queue []refreshGroup // queue groups waiting for refresh in actual round
+}
+
+// refreshGroup index of server and group for refresh
+type refreshGroup struct {
+	monitor int
+	group   int
 }
 
 // openSessionResponse response for open session to server
 type openSessionResponse struct {
 	XMLName       xml.Name `xml:"perfmonOpenSessionResponse"`
-	Text          string   `xml:",chardata"`
-	Ns1           string   `xml:"ns1,attr"`
 	OpenSessionId string   `xml:"perfmonOpenSessionReturn"`
 }
 
@@ -40,41 +53,75 @@ func (s *PerfMonService) OpenSession() (err error) {
 	log.WithFields(s.logFields("OpenSession")).Trace("open new session")
 	defer duration(track(s.logFields("OpenSession"), "procedure ends"))
 	req := " <soap:perfmonOpenSession/>"
-	body, err := s.client.processRequest("OpenSession", req)
-
-	if err != nil {
-		log.WithFields(s.logFields("OpenSession")).Errorf("session request fail with message %s", err)
-		return err
-	}
-
 	var data openSessionResponse
-	err = xml.Unmarshal([]byte(body), &data)
+	err = s.client.processRequest("OpenSession", req, &data)
 	if err != nil {
-		log.WithFields(s.logFields("OpenSession")).Errorf("problem convert XML body to struct. Error: %s", err)
+		log.WithFields(s.logFields("OpenSession")).Errorf("session request fail with message %s", err)
 		return err
 	}
-	s.client.session = data.OpenSessionId
-	log.WithFields(s.logFields("OpenSession", s.client.session)).Infof("open new monitoring session")
+	s.client.setSession(data.OpenSessionId)
+	monitorStatus.sessionOpened(time.Now())
+	log.WithFields(s.logFields("OpenSession", s.client.sessionId())).Infof("open new monitoring session")
 	return nil
 }
 
 // AddCounters add PerfMon session counters
 func (s *PerfMonService) AddCounters() {
-	log.WithFields(s.logFields("AddCounters", s.client.session)).Trace("register counters for session")
+	log.WithFields(s.logFields("AddCounters", s.client.sessionId())).Trace("register counters for session")
 	if !s.client.isSessionOpen() {
 		log.WithFields(s.logFields("AddCounters")).Debug("session not open")
 		return
 	}
 	cnt := 0
-	for _, mon := range s.monitors {
-		if mon.AddCounters(s.client) != nil {
-			log.WithFields(s.logFields("AddCounters", s.client.session)).Errorf("problem register counter toi session for monitor %s", mon.server)
+	for m := range s.monitors {
+		if s.monitors[m].AddCounters(s.client) != nil {
+			log.WithFields(s.logFields("AddCounters", s.client.sessionId())).Errorf("problem register counter toi session for monitor %s", s.monitors[m].server)
 		} else {
 			cnt++
 		}
 	}
 	if cnt == len(s.monitors) {
-		log.WithFields(s.logFields("AddCounters", s.client.session)).Info("success register counters for session")
+		log.WithFields(s.logFields("AddCounters", s.client.sessionId())).Info("success register counters for session")
+	}
+	s.refresh = instanceRefresh{next: time.Now().Add(time.Duration(config.InstanceRefresh) * time.Minute)}
+}
+
+// RefreshInstances periodically add new and remove deleted instances of multi instance objects in session
+//   - every InstanceRefresh minutes all groups are queued and one group is refreshed per minute for keep API rate
+func (s *PerfMonService) RefreshInstances(now time.Time) {
+	if config.InstanceRefresh <= 0 || !s.client.isSessionOpen() {
+		return
+	}
+	if len(s.refresh.queue) == 0 {
+		if now.Before(s.refresh.next) {
+			return
+		}
+		s.refresh.next = now.Add(time.Duration(config.InstanceRefresh) * time.Minute)
+		for m := range s.monitors {
+			for g, group := range s.monitors[m].counterList.group {
+				if group.multiInstance {
+					s.refresh.queue = append(s.refresh.queue, refreshGroup{monitor: m, group: g})
+				}
+			}
+		}
+	}
+	if len(s.refresh.queue) == 0 || now.Sub(s.refresh.step) < time.Minute {
+		return
+	}
+	step := s.refresh.queue[0]
+	s.refresh.queue = s.refresh.queue[1:]
+	s.refresh.step = now
+	h := &s.monitors[step.monitor]
+	groupName := h.counterList.group[step.group].groupName
+	added, removed, err := h.RefreshInstances(s.client, step.group)
+	if err != nil {
+		log.WithFields(h.logFields("RefreshInstances")).Errorf("problem refresh instances of %s. Error: %s", groupName, err)
+	}
+	for _, instance := range removed {
+		objectMetricsRemoveInstance(h.server, groupName, instance)
+	}
+	if len(added) > 0 || len(removed) > 0 {
+		log.WithFields(h.logFields("RefreshInstances")).Infof("instances of %s refreshed, added %d, removed %d", groupName, len(added), len(removed))
 	}
 }
 
@@ -86,10 +133,13 @@ func (s *PerfMonService) CloseSession() {
 		log.WithFields(s.logFields("CloseSession")).Debug("not any open session")
 		return
 	}
-	req := fmt.Sprintf("<soap:perfmonCloseSession><soap:SessionHandle>%s</soap:SessionHandle></soap:perfmonCloseSession>", s.client.session)
-	_, _ = s.client.processRequest("CloseSession", req)
-	log.WithFields(s.logFields("CloseSession", s.client.session)).Debug("current session is closed")
-	s.client.session = ""
+	req := fmt.Sprintf("<soap:perfmonCloseSession><soap:SessionHandle>%s</soap:SessionHandle></soap:perfmonCloseSession>", s.client.sessionId())
+	_ = s.client.processRequest("CloseSession", req, nil)
+	log.WithFields(s.logFields("CloseSession", s.client.sessionId())).Debug("current session is closed")
+	s.client.setSession("")
+	monitorStatus.sessionClosed()
+	counterValues.reset()
+	valueStream.sessionDown(time.Now())
 	prometheusRemoveMetrics()
 }
 
@@ -99,27 +149,27 @@ func (s *PerfMonService) ExistSession() bool {
 }
 
 func (s *PerfMonService) CollectSessionData() (err error) {
-	log.WithFields(s.logFields("CollectSessionData", s.client.session)).Trace("collect session data")
+	log.WithFields(s.logFields("CollectSessionData", s.client.sessionId())).Trace("collect session data")
 	defer duration(track(s.logFields("CollectSessionData"), "procedure ends"))
 
 	if !s.client.isSessionOpen() {
 		log.WithFields(s.logFields("CollectSessionData")).Debug("session not open")
 		return errors.New("session not exist for open data")
 	}
-	req := fmt.Sprintf("<soap:perfmonCollectSessionData><soap:SessionHandle>%s</soap:SessionHandle></soap:perfmonCollectSessionData>", s.client.session)
-	body, err := s.client.processRequest("CollectSessionData", req)
+	start := time.Now()
+	req := fmt.Sprintf("<soap:perfmonCollectSessionData><soap:SessionHandle>%s</soap:SessionHandle></soap:perfmonCollectSessionData>", s.client.sessionId())
+	var data SessionData
+	err = s.client.processRequest("CollectSessionData", req, &data)
 	if err != nil {
 		log.WithFields(s.logFields("CollectSessionData")).Errorf("request return error message %s", err)
 		return err
 	}
-
-	var data SessionData
-	err = xml.Unmarshal([]byte(body), &data)
-	if err != nil {
-		log.WithFields(s.logFields("CollectSessionData", s.client.session)).Errorf("problem convert XML body to required struct. Error: %s", err)
-		return err
+	changed := data.processData()
+	monitorStatus.collected(start, &data)
+	if history != nil {
+		history.write(start, &data)
 	}
-	data.processData()
+	valueStream.publish(changed, time.Now())
 	return nil
 }
 
@@ -128,22 +178,27 @@ func (s *PerfMonService) ListAllCounters() (err error) {
 	log.WithFields(s.logFields("ListAllCounters")).Trace("collect all counters")
 	defer duration(track(s.logFields("ListAllCounters"), "procedure ends"))
 	err = nil
+	descriptions := make(map[string]string)
 	for r := range s.monitors {
 		e := s.monitors[r].ListCounters(s.client)
 		if e != nil {
 			err = e
 		}
-		e = s.monitors[r].ReadCounterDescription(s.client)
+		e = s.monitors[r].ReadCounterDescription(s.client, descriptions)
 		if e != nil {
 			err = e
 		}
 	}
+	storeDescriptions(descriptions)
 	return err
 }
 
 func (s *PerfMonService) GetCounterDetails(name string) (details *CounterDetails, err error) {
 	for srv, server := range s.monitors {
 		for g, group := range server.counterList.group {
+			if !inSlice(group.groupName, AllowedGroupNames) {
+				continue
+			}
 			for c, counter := range group.counterName {
 				if counter.name == name {
 					return &s.monitors[srv].counterList.group[g].counterName[c], nil
@@ -156,6 +211,24 @@ func (s *PerfMonService) GetCounterDetails(name string) (details *CounterDetails
 	return details, fmt.Errorf("problem found required counter [%s] on any server", name)
 }
 
+// GetGroupCounterDetails find details for counter in required group (PerfMon object)
+func (s *PerfMonService) GetGroupCounterDetails(groupName string, name string) (details *CounterDetails, err error) {
+	for srv, server := range s.monitors {
+		for g, group := range server.counterList.group {
+			if group.groupName != groupName {
+				continue
+			}
+			for c, counter := range group.counterName {
+				if counter.name == name && len(counter.description) > 0 {
+					return &s.monitors[srv].counterList.group[g].counterName[c], nil
+				}
+			}
+		}
+	}
+	details = &CounterDetails{name: name, description: fmt.Sprintf("Description for %s\\%s not exists", groupName, name)}
+	return details, fmt.Errorf("problem found required counter [%s\\%s] on any server", groupName, name)
+}
+
 func (s *PerfMonService) print() string {
 	return ""
 }
@@ -0,0 +1,56 @@
+package main
+
+import "fmt"
+
+const (
+	ProductCucm     = "cucm" // ProductCucm Cisco Unified Communications Manager
+	ProductUnity    = "cuc"  // ProductUnity Cisco Unity Connection
+	ProductPresence = "imp"  // ProductPresence Cisco IM and Presence
+)
+
+// ProductProfile standard groups and default enabled objects for one product with PerfMon API
+type ProductProfile struct {
+	name        string   // name used in configuration
+	description string   // description of product
+	groupNames  []string // groupNames groups with standard counters (SupportedCounters), used as AllowedGroupNames
+	objects     []string // objects configuration names of objects enabled when not defined in configuration
+}
+
+// ProductProfiles supported products
+var ProductProfiles = []ProductProfile{
+	{name: ProductCucm, description: "Cisco Unified Communications Manager", groupNames: []string{"Cisco CallManager", "Cisco Recording"}},
+	{name: ProductUnity, description: "Cisco Unity Connection", groupNames: []string{}, objects: []string{"cucSessions", "cucMessageStore", "cucPorts"}},
+	{name: ProductPresence, description: "Cisco IM and Presence", groupNames: []string{}, objects: []string{"impXcpSessions", "impPresence", "impSipProxy"}},
+}
+
+// productProfile find product profile by name
+func productProfile(name string) *ProductProfile {
+	for i := range ProductProfiles {
+		if ProductProfiles[i].name == name {
+			return &ProductProfiles[i]
+		}
+	}
+	return nil
+}
+
+// apply switch standard groups to product groups
+func (p *ProductProfile) apply() {
+	AllowedGroupNames = p.groupNames
+}
+
+// objectDefault is object enabled by product when it isn't defined in configuration
+func (p *ProductProfile) objectDefault(configName string) bool {
+	return inSlice(configName, p.objects)
+}
+
+// standardCounters standard counters supported by product, empty when product hasn't groups with standard counters
+func standardCounters() []Counters {
+	if len(AllowedGroupNames) == 0 {
+		return nil
+	}
+	return SupportedCounters
+}
+
+func (p *ProductProfile) Print() string {
+	return fmt.Sprintf("Product:              [%s - %s]\r\n", p.name, p.description)
+}
@@ -20,13 +20,24 @@ var (
 	callMetrics map[string]*prometheus.GaugeVec
 	// counterMetrics list of counter metrics (i.e. failure recorded calls)
 	counterMetrics map[string]*prometheus.CounterVec
-	// counterActual actual presented value in counterMetrics
+	// counterActual actual presented value in counterMetrics by server and counter
 	counterActual map[string]float64
-)
-
-const (
-	mainPage = "<html><head><title>%s</title></head><body>%s</body></html>"
-	aHref    = "<a href=\"%s\">%s</a><br>"
+	// gaugeSampling min/max/avg of gauge metrics between scrapes, nil when sampling is disabled
+	gaugeSampling *GaugeSampling
+	// clusterAggregation cluster aggregates of configured counters, nil when not any rule is defined
+	clusterAggregation *ClusterAggregation
+	// locationHeadroom bandwidth headroom of locations, nil when Cisco Locations LBM object isn't enabled
+	locationHeadroom *LocationHeadroom
+	// sipTrunkFailures derived failed calls of SIP trunks, nil when Cisco SIP object isn't enabled
+	sipTrunkFailures *SipTrunkFailures
+	// tftpBuild TFTP build in progress signal, nil when Cisco TFTP object isn't enabled
+	tftpBuild *TftpBuild
+	// busyHour busy hour call attempts and peak concurrency computation, nil when disabled
+	busyHour *BusyHour
+	// counterStatusMetrics last CStatus of collected counters with not valid data
+	counterStatusMetrics *prometheus.GaugeVec
+	// apiErrorMetrics number of PerfMon API errors by type
+	apiErrorMetrics *prometheus.CounterVec
 )
 
 // newWebServer create web server structure
@@ -35,34 +46,24 @@ func newWebServer(quit chan<- os.Signal) *http.Server {
 	toStopChannel = make(chan bool)
 	router := http.NewServeMux()
 
-	router.Handle("/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		log.WithFields(log.Fields{"metricsUri": "/", FieldRoutine: "newWebServer"}).Debug("request /")
-		w.WriteHeader(http.StatusOK)
-		body := fmt.Sprintf(aHref, "/metrics", "Prometheus Export Data")
-		body += fmt.Sprintf(aHref, "/status", "Program status")
-		body += fmt.Sprintf(aHref, "/config", "Program configuration")
-		body += fmt.Sprintf(aHref, "/version", "Program version")
-		if config.AllowStop {
-			body += fmt.Sprintf(aHref, "/stop", "Stop program")
-		}
-		_, _ = w.Write([]byte(fmt.Sprintf(mainPage, applicationName, body)))
-	}))
+	router.HandleFunc("/", dashboardHandler)
 	router.Handle("/version", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		log.WithFields(log.Fields{"metricsUri": "/version", FieldRoutine: "newWebServer"}).Debug("request /version")
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte(version.Print(applicationName)))
 	}))
-	router.Handle("/status", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		log.WithFields(log.Fields{"metricsUri": "/err", FieldRoutine: "newWebServer"}).Debug("request /status")
-		w.WriteHeader(http.StatusNotFound)
-		_, _ = w.Write([]byte(monitors.client.print()))
-	}))
+	router.HandleFunc("/status", dashboardHandler)
 	router.Handle("/config", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		log.WithFields(log.Fields{"metricsUri": "/config", FieldRoutine: "newWebServer"}).Debug("request /config")
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte(config.print()))
 	}))
 	router.Handle("/metrics", promhttp.Handler())
+	router.HandleFunc("/counters", countersHandler)
+	registerApi(router)
+	if config.Query.Enabled {
+		router.HandleFunc("/query", queryHandler)
+	}
 	router.HandleFunc("/stop", func(writer http.ResponseWriter, request *http.Request) {
 		log.WithFields(log.Fields{"metricsUri": "/stop", FieldRoutine: "newWebServer"}).Infof("request from %s", request.URL.Path)
 		if config.AllowStop {
@@ -83,6 +84,7 @@ func newWebServer(quit chan<- os.Signal) *http.Server {
 	})
 	port := fmt.Sprintf(":%d", config.Port)
 	server := &http.Server{Handler: router, Addr: port}
+	server.RegisterOnShutdown(valueStream.close)
 
 	log.WithFields(log.Fields{"port": port, "metricsUri": "/metrics", FieldRoutine: "newWebServer"}).Infof("listener start on 0.0.0.0:%d/metrics", config.Port)
 	return server
@@ -95,6 +97,10 @@ func prometheusCreateMetrics() {
 	callMetrics = make(map[string]*prometheus.GaugeVec)
 	counterMetrics = make(map[string]*prometheus.CounterVec)
 	counterActual = make(map[string]float64)
+	gaugeSampling = nil
+	if config.Sampling.Enabled {
+		gaugeSampling = NewGaugeSampling(time.Duration(config.Sampling.Window) * time.Second)
+	}
 
 	var counter *CounterDetails
 	var err error
@@ -105,7 +111,7 @@ func prometheusCreateMetrics() {
 		prometheus.Unregister(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
 	}
 
-	for _, supportedCounter := range SupportedCounters {
+	for _, supportedCounter := range standardCounters() {
 		if !config.Metrics.enablePrometheusCounter(supportedCounter.allowedCounterName) {
 			log.WithFields(log.Fields{FieldRoutine: "newWebServer", FieldMetricsName: supportedCounter.prometheusName}).Debugf("metrics %s not enabled", supportedCounter.allowedCounterName)
 			continue
@@ -124,7 +130,6 @@ func prometheusCreateMetrics() {
 					Help: counter.description,
 				}, []string{"server"})
 			prometheus.MustRegister(counterMetrics[supportedCounter.allowedCounterName])
-			counterActual[supportedCounter.allowedCounterName] = float64(0)
 		} else {
 			callMetrics[supportedCounter.allowedCounterName] = prometheus.NewGaugeVec(
 				prometheus.GaugeOpts{
@@ -135,19 +140,75 @@ func prometheusCreateMetrics() {
 			for _, srv := range monitors.monitors {
 				callMetrics[supportedCounter.allowedCounterName].WithLabelValues(srv.server).Set(0)
 			}
+			if gaugeSampling != nil {
+				gaugeSampling.addCounter(supportedCounter.allowedCounterName, supportedCounter.prometheusName, counter.description, []string{"server"})
+			}
 		}
 	}
+	objectMetricsCreate()
+	counterStatusMetrics = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "cucm_counter_status",
+			Help: "Last CStatus of counter with not valid data (other than 0 or 1), samples with this status are not exported",
+		}, []string{"server", "object", "instance", "counter"})
+	prometheus.MustRegister(counterStatusMetrics)
+	if gaugeSampling != nil {
+		prometheus.MustRegister(gaugeSampling)
+	}
+	locationHeadroom = nil
+	if object := supportedObject(LocationHeadroomGroup); object != nil && object.enabled() {
+		locationHeadroom = NewLocationHeadroom()
+		prometheus.MustRegister(locationHeadroom)
+	}
+	sipTrunkFailures = nil
+	if object := supportedObject(SipTrunkGroup); object != nil && object.enabled() {
+		sipTrunkFailures = NewSipTrunkFailures()
+		prometheus.MustRegister(sipTrunkFailures)
+	}
+	tftpBuild = nil
+	if object := supportedObject(TftpBuildGroup); object != nil && object.enabled() {
+		tftpBuild = NewTftpBuild()
+		prometheus.MustRegister(tftpBuild)
+	}
+	clusterAggregation = nil
+	if len(config.Aggregation) > 0 {
+		clusterAggregation = NewClusterAggregation(config.Aggregation)
+		prometheus.MustRegister(clusterAggregation)
+	}
+}
+
+// prometheusCreateApiMetrics create metrics for PerfMon API client, exists for whole program life
+func prometheusCreateApiMetrics() {
+	log.WithFields(log.Fields{FieldRoutine: "prometheusCreateApiMetrics"}).Debug("prepare API client metrics")
+	apiErrorMetrics = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cucm_perfmon_api_errors_total",
+			Help: "Number of failed PerfMon API requests by error type",
+		}, []string{"type"})
+	prometheus.MustRegister(apiErrorMetrics)
+	for _, kind := range ApiErrorKinds {
+		apiErrorMetrics.WithLabelValues(string(kind)).Add(0)
+	}
+}
+
+// prometheusCreateBusyHour create busy hour computation with persisted state, exists for whole program life
+func prometheusCreateBusyHour() {
+	if !config.BusyHour.Enabled {
+		return
+	}
+	log.WithFields(log.Fields{FieldRoutine: "prometheusCreateBusyHour"}).Debug("prepare busy hour metrics")
+	busyHour = NewBusyHour(config.BusyHour.StateFile)
+	prometheus.MustRegister(busyHour)
 }
 
 // prometheusRemoveMetrics remove all CUCM metrics from prometheus
 func prometheusRemoveMetrics() {
 	log.WithFields(log.Fields{FieldRoutine: "prometheusCreateMetrics"}).Infof("prepare remove all metrics")
 	defer duration(track(log.Fields{FieldRoutine: "prometheusCreateMetrics"}, "procedure ends"))
-	for _, cnt := range SupportedCounters {
+	for _, cnt := range standardCounters() {
 		if config.Metrics.enablePrometheusCounter(cnt.allowedCounterName) {
 			if strings.HasSuffix(strings.ToLower(cnt.allowedCounterName), "failed") {
 				prometheus.Unregister(counterMetrics[cnt.allowedCounterName])
-				counterActual[cnt.allowedCounterName] = float64(0)
 			} else {
 				prometheus.Unregister(callMetrics[cnt.allowedCounterName])
 				for _, srv := range monitors.monitors {
@@ -156,6 +217,26 @@ func prometheusRemoveMetrics() {
 			}
 		}
 	}
+	counterActual = make(map[string]float64)
+	objectMetricsRemove()
+	if counterStatusMetrics != nil {
+		prometheus.Unregister(counterStatusMetrics)
+	}
+	if gaugeSampling != nil {
+		prometheus.Unregister(gaugeSampling)
+	}
+	if locationHeadroom != nil {
+		prometheus.Unregister(locationHeadroom)
+	}
+	if sipTrunkFailures != nil {
+		prometheus.Unregister(sipTrunkFailures)
+	}
+	if tftpBuild != nil {
+		prometheus.Unregister(tftpBuild)
+	}
+	if clusterAggregation != nil {
+		prometheus.Unregister(clusterAggregation)
+	}
 }
 
 // gracefullyShutdown shutdown all services, web servers and GO routines
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// EnvelopeCollectCounterData read actual values of all counters of object without session
+	EnvelopeCollectCounterData = "<soap:perfmonCollectCounterData>\r\n<soap:Host>%s</soap:Host>\r\n<soap:Object>%s</soap:Object>\r\n</soap:perfmonCollectCounterData>"
+	// QueryDescriptionLimit maximal number of description requests per query, fits to requests reserved by rate budget
+	QueryDescriptionLimit = 5
+)
+
+// XmlCollectCounterDataResponse response for collect counter data, same items as session data
+type XmlCollectCounterDataResponse struct {
+	XMLName     xml.Name         `xml:"perfmonCollectCounterDataResponse"`
+	CollectData []OneCollectData `xml:"perfmonCollectCounterDataReturn"`
+}
+
+// CounterPath parsed counter path \\node\Object(instance)\Counter, instance and counter can contain wildcards
+type CounterPath struct {
+	Host     string
+	Object   string
+	Instance string
+	Counter  string
+	instance *regexp.Regexp // instance compiled instance wildcard pattern
+	counter  *regexp.Regexp // counter compiled counter wildcard pattern
+}
+
+// QueryCounter one counter value returned by query
+type QueryCounter struct {
+	Name        string  `json:"name"`
+	Instance    string  `json:"instance,omitempty"`
+	Value       float64 `json:"value"`
+	CStatus     string  `json:"cStatus"`
+	Valid       bool    `json:"valid"`
+	Description string  `json:"description"`
+}
+
+// descriptionLookup read unknown descriptions for one query, number of PerfMon API requests is limited
+type descriptionLookup struct {
+	remaining int                               // remaining number of allowed requests, 0 read only known descriptions
+	read      func(name string) (string, error) // read description of counter from PerfMon API
+}
+
+// QueryResult result of ad-hoc counter query
+type QueryResult struct {
+	Path     string         `json:"path"`
+	Counters []QueryCounter `json:"counters"`
+}
+
+var (
+	// ErrInvalidPath counter path isn't in format \\node\Object(instance)\Counter
+	ErrInvalidPath = errors.New("counter path must be in format \\\\node\\Object(instance)\\Counter")
+	// queryDescriptions descriptions of collected and queried counters by object key, HTTP handlers don't read counter lists
+	queryDescriptions = make(map[string]string)
+	queryMutex        sync.RWMutex
+)
+
+// globRegexp compile wildcard pattern, * match any characters (include \ and /) and ? match one character
+//   - other characters are literal, i.e. [kilobytes] in counter name
+func globRegexp(pattern string) *regexp.Regexp {
+	expr := strings.Builder{}
+	expr.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			expr.WriteString(".*")
+		case '?':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	expr.WriteString("$")
+	return regexp.MustCompile(expr.String())
+}
+
+// ParseCounterPath parse counter path \\node\Object(instance)\Counter, instance is optional
+func ParseCounterPath(counterPath string) (*CounterPath, error) {
+	parts := strings.Split(strings.TrimLeft(counterPath, "\\"), "\\")
+	if len(parts) != 3 || len(parts[0]) == 0 || len(parts[1]) == 0 || len(parts[2]) == 0 {
+		return nil, ErrInvalidPath
+	}
+	object, instance := splitInstance(parts[1])
+	if len(object) == 0 {
+		return nil, ErrInvalidPath
+	}
+	return &CounterPath{Host: parts[0], Object: object, Instance: instance, Counter: parts[2],
+		instance: globRegexp(instance), counter: globRegexp(parts[2])}, nil
+}
+
+// match is collected counter name match path, wildcards are allowed in instance and counter
+func (c *CounterPath) match(instance string, counter string) bool {
+	return c.instance.MatchString(instance) && c.counter.MatchString(counter)
+}
+
+// QueryCounter read actual values of counters matching path without monitoring session
+//   - unknown descriptions are read from PerfMon API only when describe is requested, one request per counter name
+//     and at most QueryDescriptionLimit requests, other unknown descriptions stay empty
+func (s *PerfMonService) QueryCounter(counterPath string, describe bool) (*QueryResult, error) {
+	log.WithFields(s.logFields("QueryCounter")).Debugf("query counter %s", counterPath)
+	defer duration(track(s.logFields("QueryCounter"), "procedure ends"))
+	p, err := ParseCounterPath(counterPath)
+	if err != nil {
+		return nil, err
+	}
+	var data XmlCollectCounterDataResponse
+	err = s.client.processRequest("QueryCounter", fmt.Sprintf(EnvelopeCollectCounterData, xmlText(p.Host), xmlText(p.Object)), &data)
+	if err != nil {
+		return nil, err
+	}
+	result := &QueryResult{Path: counterPath, Counters: make([]QueryCounter, 0)}
+	lookup := descriptionLookup{read: s.readDescription}
+	if describe {
+		lookup.remaining = QueryDescriptionLimit
+	}
+	for _, item := range data.CollectData {
+		_, group, counter, err := item.splitName()
+		if err != nil {
+			continue
+		}
+		_, instance := splitInstance(group)
+		if !p.match(instance, counter) {
+			continue
+		}
+		result.Counters = append(result.Counters, QueryCounter{
+			Name:        item.Name,
+			Instance:    instance,
+			Value:       item.Value,
+			CStatus:     item.CStatus,
+			Valid:       item.valid(),
+			Description: lookup.description(p.Object, counter, item.Name),
+		})
+	}
+	return result, nil
+}
+
+// description of counter from known descriptions or PerfMon API while requests remain
+//   - after failed request other descriptions aren't read
+func (l *descriptionLookup) description(object string, counter string, name string) string {
+	description, ok := knownDescription(object, counter)
+	if ok || l.remaining <= 0 {
+		return description
+	}
+	l.remaining--
+	description, err := l.read(name)
+	if err != nil {
+		log.WithFields(log.Fields{FieldRoutine: "QueryCounter"}).Warnf("problem read description of %s, other descriptions are skipped. Error: %s", name, err)
+		l.remaining = 0
+		return ""
+	}
+	storeDescriptions(map[string]string{objectKey(object, counter): description})
+	return description
+}
+
+// readDescription read description of counter from PerfMon API
+func (s *PerfMonService) readDescription(name string) (string, error) {
+	var response XmlDescriptionCounterResponse
+	if err := s.client.processRequest("QueryCounterDescription", fmt.Sprintf(QueryCounterDescription, xmlText(name)), &response); err != nil {
+		return "", err
+	}
+	return response.QueryCounterDescriptionReturn, nil
+}
+
+// knownDescription description of collected or already queried counter
+func knownDescription(object string, counter string) (string, bool) {
+	queryMutex.RLock()
+	defer queryMutex.RUnlock()
+	description, ok := queryDescriptions[objectKey(object, counter)]
+	return description, ok
+}
+
+// storeDescriptions add descriptions of collected counters read when session starts
+func storeDescriptions(descriptions map[string]string) {
+	queryMutex.Lock()
+	defer queryMutex.Unlock()
+	for key, description := range descriptions {
+		queryDescriptions[key] = description
+	}
+}
+
+// queryAuthorized check basic authentication of query request
+func queryAuthorized(r *http.Request) bool {
+	user, password, ok := r.BasicAuth()
+	if !ok {
+		return false
+	}
+	userOk := subtle.ConstantTimeCompare([]byte(user), []byte(config.Query.User)) == 1
+	passwordOk := subtle.ConstantTimeCompare([]byte(password), []byte(config.Query.Password)) == 1
+	return userOk && passwordOk
+}
+
+// queryHandler HTTP handler for /query?path=\\node\Object(instance)\Counter
+func queryHandler(w http.ResponseWriter, r *http.Request) {
+	log.WithFields(log.Fields{"metricsUri": "/query", FieldRoutine: "queryHandler"}).Debug("request /query")
+	if !queryAuthorized(r) {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf("Basic realm=\"%s\"", applicationName))
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	counterPath := r.URL.Query().Get("path")
+	result, err := monitors.QueryCounter(counterPath, r.URL.Query().Get("describe") == "1")
+	if err != nil {
+		status := http.StatusBadGateway
+		switch {
+		case errors.Is(err, ErrInvalidPath):
+			status = http.StatusBadRequest
+		case errors.Is(err, ErrInvalidCounter):
+			status = http.StatusNotFound
+		case errors.Is(err, ErrRateLimit):
+			status = http.StatusTooManyRequests
+		}
+		writeJson(w, status, map[string]string{"path": counterPath, "error": err.Error()})
+		return
+	}
+	writeJson(w, http.StatusOK, result)
+}
+
+// writeJson write data as JSON response
+func writeJson(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		log.WithFields(log.Fields{FieldRoutine: "writeJson"}).Errorf("problem write JSON response. Error: %s", err)
+	}
+}
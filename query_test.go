@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestParseCounterPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		object   string
+		instance string
+		counter  string
+	}{
+		{path: "\\\\node1\\Cisco CallManager\\CallsActive", object: "Cisco CallManager", counter: "CallsActive"},
+		{path: "\\\\node1\\Cisco SIP(trunk01)\\CallsActive", object: "Cisco SIP", instance: "trunk01", counter: "CallsActive"},
+		{path: "\\\\node1\\Cisco SIP(*)\\Calls*", object: "Cisco SIP", instance: "*", counter: "Calls*"},
+	}
+	for _, tt := range tests {
+		p, err := ParseCounterPath(tt.path)
+		if err != nil {
+			t.Errorf("path %s isn't parsed. Error: %s", tt.path, err)
+			continue
+		}
+		if p.Host != "node1" || p.Object != tt.object || p.Instance != tt.instance || p.Counter != tt.counter {
+			t.Errorf("path %s parsed as %+v", tt.path, p)
+		}
+	}
+
+	for _, invalid := range []string{"", "\\\\node1", "\\\\node1\\Cisco SIP", "\\\\node1\\\\CallsActive", "\\\\node1\\(trunk01)\\CallsActive", "\\\\node1\\a\\b\\c"} {
+		if _, err := ParseCounterPath(invalid); !errors.Is(err, ErrInvalidPath) {
+			t.Errorf("invalid path %q return error %v", invalid, err)
+		}
+	}
+}
+
+func TestCounterPathMatch(t *testing.T) {
+	tests := []struct {
+		path     string
+		instance string
+		counter  string
+		match    bool
+	}{
+		{path: "\\\\node1\\Cisco SIP(*)\\CallsActive", instance: "trunk01", counter: "CallsActive", match: true},
+		{path: "\\\\node1\\Cisco SIP(*)\\CallsActive", instance: "trunk01", counter: "CallsAttempted", match: false},
+		// wildcard match any characters include slash
+		{path: "\\\\node1\\Process(*)\\VmSize", instance: "java/tomcat", counter: "VmSize", match: true},
+		{path: "\\\\node1\\Partition(?)\\% Used", instance: "/", counter: "% Used", match: true},
+		{path: "\\\\node1\\Cisco SIP(trunk0?)\\Calls*", instance: "trunk01", counter: "CallsInProgress", match: true},
+		{path: "\\\\node1\\Cisco SIP(trunk0?)\\Calls*", instance: "trunk010", counter: "CallsInProgress", match: false},
+		// brackets are literal
+		{path: "\\\\node1\\CUC Message Store\\Message Size Average [kilobytes]", counter: "Message Size Average [kilobytes]", match: true},
+		{path: "\\\\node1\\CUC Message Store\\Message Size Average [kilobytes]", counter: "Message Size Average k", match: false},
+		// path without instance match only single instance object
+		{path: "\\\\node1\\Cisco CallManager\\CallsActive", instance: "trunk01", counter: "CallsActive", match: false},
+	}
+	for _, tt := range tests {
+		p, err := ParseCounterPath(tt.path)
+		if err != nil {
+			t.Fatalf("path %s isn't parsed. Error: %s", tt.path, err)
+		}
+		if got := p.match(tt.instance, tt.counter); got != tt.match {
+			t.Errorf("path %s match instance %q counter %q is %t, want %t", tt.path, tt.instance, tt.counter, got, tt.match)
+		}
+	}
+}
+
+func TestDescriptionLookupLimit(t *testing.T) {
+	requests := 0
+	lookup := descriptionLookup{remaining: QueryDescriptionLimit, read: func(name string) (string, error) {
+		requests++
+		return "description of " + name, nil
+	}}
+	storeDescriptions(map[string]string{objectKey("Cisco Limit", "Known"): "known description"})
+	if got := lookup.description("Cisco Limit", "Known", "\\\\node1\\Cisco Limit\\Known"); got != "known description" || requests != 0 {
+		t.Errorf("known description %q read with %d requests", got, requests)
+	}
+	for i := 0; i < QueryDescriptionLimit+3; i++ {
+		counter := fmt.Sprintf("Counter%d", i)
+		got := lookup.description("Cisco Limit", counter, "\\\\node1\\Cisco Limit\\"+counter)
+		if want := i < QueryDescriptionLimit; (got != "") != want {
+			t.Errorf("description of %s %q, want read %t", counter, got, want)
+		}
+	}
+	// same counter of other instance uses cached description
+	lookup.description("Cisco Limit", "Counter0", "\\\\node1\\Cisco Limit(2)\\Counter0")
+	if requests != QueryDescriptionLimit {
+		t.Errorf("lookup made %d requests, want %d", requests, QueryDescriptionLimit)
+	}
+}
+
+func TestDescriptionLookupError(t *testing.T) {
+	requests := 0
+	lookup := descriptionLookup{remaining: QueryDescriptionLimit, read: func(name string) (string, error) {
+		requests++
+		return "", ErrRateLimit
+	}}
+	lookup.description("Cisco Error", "Counter1", "\\\\node1\\Cisco Error\\Counter1")
+	lookup.description("Cisco Error", "Counter2", "\\\\node1\\Cisco Error\\Counter2")
+	if requests != 1 {
+		t.Errorf("lookup made %d requests after error, want 1", requests)
+	}
+}
@@ -4,45 +4,115 @@ import (
 	"encoding/xml"
 	"errors"
 	log "github.com/sirupsen/logrus"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type SessionData struct {
 	XMLName     xml.Name         `xml:"perfmonCollectSessionDataResponse"`
-	Text        string           `xml:",chardata"`
-	Ns1         string           `xml:"ns1,attr"`
 	CollectData []OneCollectData `xml:"perfmonCollectSessionDataReturn"`
 }
 
 type OneCollectData struct {
-	Text    string  `xml:",chardata"`
 	Name    string  `xml:"Name"`
 	Value   float64 `xml:"Value"`
 	CStatus string  `xml:"CStatus"`
 }
 
-// processData base on collected data update Prometheus metrics
-func (s *SessionData) processData() {
-	var server, counter string
+// processData base on collected data update Prometheus metrics, return changed values
+func (s *SessionData) processData() []CounterValue {
+	var server, group, counter string
 	var err error
-	for _, data := range s.CollectData {
-		server, _, counter, err = data.splitName()
+	now := time.Now()
+	changed := make([]CounterValue, 0)
+	for i, data := range s.CollectData {
+		server, group, counter, err = data.splitName()
 		if err != nil {
 			continue
 		}
+		if value, ok := counterValues.observe(server, group, counter, &s.CollectData[i], now); ok {
+			changed = append(changed, value)
+		}
+		if counterStatusMetrics != nil {
+			object, instance := splitInstance(group)
+			if data.valid() {
+				counterStatusMetrics.DeleteLabelValues(server, object, instance, counter)
+			} else {
+				counterStatusMetrics.WithLabelValues(server, object, instance, counter).Set(data.status())
+			}
+		}
+		if !data.valid() {
+			// invalid sample (i.e. not ready counter), metric keep previous value
+			log.WithFields(log.Fields{FieldRoutine: "processData", "name": data.Name, "status": data.CStatus}).Debug("skip invalid counter value")
+			continue
+		}
+		if busyHour != nil {
+			busyHour.observe(server, group, counter, data.Value)
+		}
+		if clusterAggregation != nil {
+			clusterAggregation.observe(server, group, counter, data.Value)
+		}
+		if locationHeadroom != nil {
+			locationHeadroom.observe(server, group, counter, data.Value)
+		}
+		if sipTrunkFailures != nil {
+			sipTrunkFailures.observe(server, group, counter, data.Value)
+		}
+		if tftpBuild != nil {
+			tftpBuild.observe(server, group, counter, data.Value)
+		}
+		objectMetricsProcess(server, group, counter, data.Value)
+		if !standardGroup(group) {
+			continue
+		}
 		if config.Metrics.enablePrometheusCounter(counter) {
 			if strings.HasSuffix(strings.ToLower(counter), "failed") {
-				newVal := data.Value - counterActual[counter]
+				key := objectKey(server, counter)
+				newVal := data.Value - counterActual[key]
 				if newVal < 0 {
-					continue
+					// counter reset after service restart, all failures from restart are new
+					newVal = data.Value
 				}
 				counterMetrics[counter].WithLabelValues(server).Add(newVal)
-				counterActual[counter] = data.Value
+				counterActual[key] = data.Value
 			} else {
 				callMetrics[counter].WithLabelValues(server).Set(data.Value)
+				if gaugeSampling != nil {
+					gaugeSampling.observe(counter, data.Value, server)
+				}
 			}
 		}
 	}
+	if busyHour != nil {
+		busyHour.update(now)
+	}
+	if clusterAggregation != nil {
+		clusterAggregation.update()
+	}
+	if tftpBuild != nil {
+		tftpBuild.update()
+	}
+	return changed
+}
+
+// valid is value marked by CUCM as valid data, CStatus 0 or 1 (valid or new data)
+func (o *OneCollectData) valid() bool {
+	status := strings.TrimSpace(o.CStatus)
+	return status == "" || status == "0" || status == "1"
+}
+
+// status numeric value of CStatus, -1 when CStatus isn't number
+func (o *OneCollectData) status() float64 {
+	status := strings.TrimSpace(o.CStatus)
+	if status == "" {
+		return 0
+	}
+	value, err := strconv.ParseInt(status, 10, 64)
+	if err != nil {
+		return -1
+	}
+	return float64(value)
 }
 
 // splitName split data path to parts include group
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestProcessDataCounterStatus(t *testing.T) {
+	counterStatusMetrics = prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "cucm_counter_status", Help: "help"},
+		[]string{"server", "object", "instance", "counter"})
+	defer func() { counterStatusMetrics = nil }()
+	name := "\\\\node1\\Cisco Test(trunk01)\\CallsActive"
+
+	data := SessionData{CollectData: []OneCollectData{{Name: name, Value: 1, CStatus: "2"}}}
+	data.processData()
+	if count := len(collectMetrics(t, counterStatusMetrics)); count != 1 {
+		t.Fatalf("exported %d status series for not valid counter, want 1", count)
+	}
+	if _, err := counterStatusMetrics.GetMetricWithLabelValues("node1", "Cisco Test", "trunk01", "CallsActive"); err != nil {
+		t.Errorf("status labels aren't split to object, instance and counter. Error: %s", err)
+	}
+
+	data = SessionData{CollectData: []OneCollectData{{Name: name, Value: 1, CStatus: "0"}}}
+	data.processData()
+	if count := len(collectMetrics(t, counterStatusMetrics)); count != 0 {
+		t.Errorf("exported %d status series for valid counter, want 0", count)
+	}
+}
+
+func TestProcessDataFailedCounter(t *testing.T) {
+	savedMetrics, savedActual, savedEnabled := counterMetrics, counterActual, config.Metrics.GatewaysSessionsFailed
+	defer func() {
+		counterMetrics, counterActual, config.Metrics.GatewaysSessionsFailed = savedMetrics, savedActual, savedEnabled
+	}()
+	vec := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "cucm_gateways_sessions_failed_total", Help: "help"}, []string{"server"})
+	counterMetrics = map[string]*prometheus.CounterVec{GatewaysSessionsFailed: vec}
+	counterActual = make(map[string]float64)
+	config.Metrics.GatewaysSessionsFailed = true
+	collect := func(server string, value float64) {
+		name := "\\\\" + server + "\\Cisco Recording\\" + GatewaysSessionsFailed
+		data := SessionData{CollectData: []OneCollectData{{Name: name, Value: value, CStatus: "0"}}}
+		data.processData()
+	}
+	value := func(server string) float64 {
+		return metricValue(collectMetrics(t, vec.WithLabelValues(server))[0])
+	}
+
+	collect("node1", 10)
+	collect("node2", 3)
+	collect("node1", 12)
+	if got := value("node1"); got != 12 {
+		t.Errorf("node1 failures %v, want 12", got)
+	}
+	if got := value("node2"); got != 3 {
+		t.Errorf("node2 failures %v, want 3", got)
+	}
+	// service restart on node2 reset source counter
+	collect("node2", 2)
+	if got := value("node2"); got != 5 {
+		t.Errorf("node2 failures after restart %v, want 5", got)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// SipTrunkGroup object with per trunk call counters
+const SipTrunkGroup = "Cisco SIP"
+
+// SipTrunkFailures compute calls of SIP trunk which were attempted but not completed
+//   - Cisco SIP object doesn't provide failed calls counter, value is attempted - completed - in progress calls
+type SipTrunkFailures struct {
+	mutex  sync.Mutex
+	desc   *prometheus.Desc
+	values map[string]*sipTrunkValues // values latest counter values by server and trunk
+}
+
+// sipTrunkValues latest call counters of one trunk
+type sipTrunkValues struct {
+	labelValues []string
+	counters    map[string]float64
+}
+
+// NewSipTrunkFailures create failed calls collector for SIP trunks
+func NewSipTrunkFailures() *SipTrunkFailures {
+	return &SipTrunkFailures{
+		desc: prometheus.NewDesc("cucm_sip_trunk_calls_failed",
+			"Calls attempted on trunk since CallManager start which are not completed and not in progress (CallsAttempted - CallsCompleted - CallsInProgress), approximation of failed calls",
+			[]string{"server", "trunk"}, nil),
+		values: make(map[string]*sipTrunkValues),
+	}
+}
+
+// observe store latest value of call counter for trunk
+func (s *SipTrunkFailures) observe(server string, group string, counter string, value float64) {
+	object, trunk := splitInstance(group)
+	if object != SipTrunkGroup || trunk == "" {
+		return
+	}
+	if counter != CallsAttempted && counter != CallsCompleted && counter != CallsInProgress {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	key := strings.Join([]string{server, trunk}, "\x00")
+	v, ok := s.values[key]
+	if !ok {
+		v = &sipTrunkValues{labelValues: []string{server, trunk}, counters: make(map[string]float64)}
+		s.values[key] = v
+	}
+	v.counters[counter] = value
+}
+
+// removeInstance forget trunk removed from session
+func (s *SipTrunkFailures) removeInstance(server string, trunk string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.values, strings.Join([]string{server, trunk}, "\x00"))
+}
+
+// Describe implements prometheus.Collector
+func (s *SipTrunkFailures) Describe(ch chan<- *prometheus.Desc) {
+	ch <- s.desc
+}
+
+// Collect implements prometheus.Collector
+//   - value isn't exported until all three source counters are collected, negative difference is exported as 0
+func (s *SipTrunkFailures) Collect(ch chan<- prometheus.Metric) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, v := range s.values {
+		attempted, okAttempted := v.counters[CallsAttempted]
+		completed, okCompleted := v.counters[CallsCompleted]
+		inProgress, okInProgress := v.counters[CallsInProgress]
+		if !okAttempted || !okCompleted || !okInProgress {
+			continue
+		}
+		failed := attempted - completed - inProgress
+		if failed < 0 {
+			failed = 0
+		}
+		ch <- prometheus.MustNewConstMetric(s.desc, prometheus.GaugeValue, failed, v.labelValues...)
+	}
+}
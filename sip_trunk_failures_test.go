@@ -0,0 +1,40 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSipTrunkFailures(t *testing.T) {
+	s := NewSipTrunkFailures()
+	s.observe("node1", "Cisco SIP(trunk01)", CallsAttempted, 120)
+	s.observe("node1", "Cisco SIP(trunk01)", CallsCompleted, 100)
+	s.observe("node1", "Cisco SIP(trunk01)", CallsInProgress, 5)
+	// trunk without all counters isn't exported
+	s.observe("node1", "Cisco SIP(trunk02)", CallsAttempted, 10)
+	s.observe("node1", "Cisco CallManager", CallsAttempted, 500)
+
+	values := make(map[string]float64)
+	for _, m := range collectMetrics(t, s) {
+		values[labelValue(m, "trunk")] = metricValue(m)
+	}
+	if !reflect.DeepEqual(values, map[string]float64{"trunk01": 15}) {
+		t.Errorf("collected %v, want trunk01 with 15 failed calls", values)
+	}
+
+	s.removeInstance("node1", "trunk01")
+	if len(s.values) != 1 {
+		t.Errorf("removed trunk is still collected")
+	}
+}
+
+func TestMissingInstances(t *testing.T) {
+	session := []string{"trunk01", "trunk02"}
+	actual := []string{"trunk02", "trunk03"}
+	if added := missingInstances(actual, session); !reflect.DeepEqual(added, []string{"trunk03"}) {
+		t.Errorf("added instances %v, want [trunk03]", added)
+	}
+	if removed := missingInstances(session, actual); !reflect.DeepEqual(removed, []string{"trunk01"}) {
+		t.Errorf("removed instances %v, want [trunk01]", removed)
+	}
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	StreamHeartbeatInterval = 15 * time.Second // interval of heartbeat events when session is down
+	StreamClientBuffer      = 16               // number of events waiting for slow client, next events are dropped
+	StreamEventValues       = "values"         // event with changed values
+	StreamEventHeartbeat    = "heartbeat"      // event with session state when session is down
+)
+
+// StreamValues data of values event
+type StreamValues struct {
+	Time   time.Time      `json:"time"`
+	Values []CounterValue `json:"values"`
+}
+
+// StreamHeartbeat data of heartbeat event
+type StreamHeartbeat struct {
+	Time           time.Time `json:"time"`
+	Connected      bool      `json:"connected"`
+	LastCollection time.Time `json:"lastCollection"`
+}
+
+// StreamEvent one server sent event
+type StreamEvent struct {
+	Name string
+	Data interface{}
+}
+
+// streamClient one connected client with its filter
+type streamClient struct {
+	filter *ValuesFilter
+	events chan StreamEvent
+}
+
+// ValueStream publish changed values to connected clients
+type ValueStream struct {
+	mutex   sync.Mutex
+	clients map[*streamClient]struct{}
+	closed  bool
+}
+
+// valueStream stream of changed values for /api/v1/stream
+var valueStream = NewValueStream()
+
+// NewValueStream create stream without clients
+func NewValueStream() *ValueStream {
+	return &ValueStream{clients: make(map[*streamClient]struct{})}
+}
+
+// subscribe add new client, nil when stream is closed
+func (s *ValueStream) subscribe(filter *ValuesFilter) *streamClient {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.closed {
+		return nil
+	}
+	c := &streamClient{filter: filter, events: make(chan StreamEvent, StreamClientBuffer)}
+	s.clients[c] = struct{}{}
+	return c
+}
+
+// unsubscribe remove client
+func (s *ValueStream) unsubscribe(c *streamClient) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if _, ok := s.clients[c]; ok {
+		delete(s.clients, c)
+		close(c.events)
+	}
+}
+
+// publish send changed values to clients, every client get only values matching its filter
+func (s *ValueStream) publish(values []CounterValue, now time.Time) {
+	if len(values) == 0 {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for c := range s.clients {
+		selected := c.filter.filter(values)
+		if len(selected) == 0 {
+			continue
+		}
+		c.send(StreamEvent{Name: StreamEventValues, Data: StreamValues{Time: now, Values: selected}})
+	}
+}
+
+// sessionDown send heartbeat to all clients immediately when session is closed
+func (s *ValueStream) sessionDown(now time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for c := range s.clients {
+		c.send(StreamEvent{Name: StreamEventHeartbeat, Data: streamHeartbeat(now)})
+	}
+}
+
+// close disconnect all clients, used when web server is shutting down
+func (s *ValueStream) close() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.closed = true
+	for c := range s.clients {
+		delete(s.clients, c)
+		close(c.events)
+	}
+}
+
+// send event without blocking, event is dropped when client is slow
+func (c *streamClient) send(event StreamEvent) {
+	select {
+	case c.events <- event:
+	default:
+		log.WithFields(log.Fields{FieldRoutine: "streamSend", "event": event.Name}).Debug("slow stream client, event dropped")
+	}
+}
+
+// filter values matching filter
+func (f *ValuesFilter) filter(values []CounterValue) []CounterValue {
+	selected := make([]CounterValue, 0)
+	for i := range values {
+		if f.match(&values[i]) {
+			selected = append(selected, values[i])
+		}
+	}
+	return selected
+}
+
+// streamHeartbeat actual session state for heartbeat event
+func streamHeartbeat(now time.Time) StreamHeartbeat {
+	status := monitorStatus.snapshot()
+	return StreamHeartbeat{Time: now, Connected: status.Connected, LastCollection: status.LastCollection}
+}
+
+// writeEvent write one server sent event and flush it to client
+func writeEvent(w http.ResponseWriter, flusher http.Flusher, event StreamEvent) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return err
+	}
+	if _, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Name, data); err != nil {
+		return err
+	}
+	flusher.Flush()
+	return nil
+}
+
+// apiStreamHandler HTTP handler for /api/v1/stream, server sent events with changed values
+func apiStreamHandler(w http.ResponseWriter, r *http.Request) {
+	log.WithFields(log.Fields{"metricsUri": r.URL.Path, FieldRoutine: "apiStreamHandler"}).Debugf("request %s from %s", r.URL.Path, r.RemoteAddr)
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeJson(w, http.StatusInternalServerError, ApiErrorResponse{Error: "streaming not supported"})
+		return
+	}
+	filter := newValuesFilter(r)
+	client := valueStream.subscribe(filter)
+	if client == nil {
+		writeJson(w, http.StatusServiceUnavailable, ApiErrorResponse{Error: "server is shutting down"})
+		return
+	}
+	defer valueStream.unsubscribe(client)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	// first event contains all actual values, next events only changed values
+	now := time.Now()
+	var err error
+	if err = writeEvent(w, flusher, StreamEvent{Name: StreamEventValues, Data: StreamValues{Time: now, Values: filter.filter(counterValues.list())}}); err != nil {
+		return
+	}
+	ticker := time.NewTicker(StreamHeartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			log.WithFields(log.Fields{FieldRoutine: "apiStreamHandler"}).Debugf("client %s disconnected", r.RemoteAddr)
+			return
+		case event, ok := <-client.events:
+			if !ok {
+				return
+			}
+			if err = writeEvent(w, flusher, event); err != nil {
+				return
+			}
+		case now = <-ticker.C:
+			heartbeat := streamHeartbeat(now)
+			if heartbeat.Connected {
+				// comment keep connection open through proxies
+				_, err = fmt.Fprint(w, ": keepalive\n\n")
+				flusher.Flush()
+			} else {
+				err = writeEvent(w, flusher, StreamEvent{Name: StreamEventHeartbeat, Data: heartbeat})
+			}
+			if err != nil {
+				return
+			}
+		}
+	}
+}
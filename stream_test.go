@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// readEvent read one server sent event, return event name and data
+func readEvent(t *testing.T, r *bufio.Reader) (name string, data string) {
+	t.Helper()
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read event error %v", err)
+		}
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "" && name != "":
+			return name, data
+		case strings.HasPrefix(line, "event: "):
+			name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+// streamServers servers of values in values event
+func streamServers(t *testing.T, data string) []string {
+	t.Helper()
+	var values StreamValues
+	if err := json.Unmarshal([]byte(data), &values); err != nil {
+		t.Fatalf("decode values event error %v", err)
+	}
+	servers := make([]string, 0, len(values.Values))
+	for _, v := range values.Values {
+		servers = append(servers, v.Server)
+	}
+	return servers
+}
+
+func TestApiStreamHandler(t *testing.T) {
+	savedStream := valueStream
+	defer func() { valueStream = savedStream; counterValues.reset() }()
+	valueStream = NewValueStream()
+	counterValues.reset()
+	now := time.Now()
+	counterValues.observe("node1", BusyHourGroup, CallsActive, &OneCollectData{Value: 1}, now)
+	counterValues.observe("node2", BusyHourGroup, CallsActive, &OneCollectData{Value: 2}, now)
+
+	server := httptest.NewServer(http.HandlerFunc(apiStreamHandler))
+	defer server.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/v1/stream?server=node1", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("content type %s, want text/event-stream", ct)
+	}
+	reader := bufio.NewReader(resp.Body)
+
+	// first event has all actual values matching filter
+	name, data := readEvent(t, reader)
+	if servers := streamServers(t, data); name != StreamEventValues || strings.Join(servers, ",") != "node1" {
+		t.Fatalf("first event %s with servers %v, want %s with node1", name, servers, StreamEventValues)
+	}
+	// next events have only changed values matching filter
+	valueStream.publish([]CounterValue{{Server: "node2", Object: BusyHourGroup, Counter: CallsActive, Value: 3}}, now)
+	valueStream.publish([]CounterValue{
+		{Server: "node2", Object: BusyHourGroup, Counter: CallsActive, Value: 4},
+		{Server: "node1", Object: BusyHourGroup, Counter: CallsActive, Value: 5},
+	}, now)
+	name, data = readEvent(t, reader)
+	if servers := streamServers(t, data); name != StreamEventValues || strings.Join(servers, ",") != "node1" {
+		t.Errorf("changed values event %s with servers %v, want %s with node1", name, servers, StreamEventValues)
+	}
+	// session down is sent as heartbeat immediately
+	valueStream.sessionDown(now)
+	if name, data = readEvent(t, reader); name != StreamEventHeartbeat || !strings.Contains(data, `"connected":false`) {
+		t.Errorf("session down event %s data %s, want disconnected %s", name, data, StreamEventHeartbeat)
+	}
+}
+
+func TestValueStreamClose(t *testing.T) {
+	s := NewValueStream()
+	c := s.subscribe(NewValuesFilter("", "", "", ""))
+	// slow client doesn't block publishing
+	for i := 0; i < StreamClientBuffer+5; i++ {
+		s.publish([]CounterValue{{Server: "node1", Counter: CallsActive, Value: float64(i)}}, time.Now())
+	}
+	if len(c.events) != StreamClientBuffer {
+		t.Errorf("client has %d waiting events, want %d", len(c.events), StreamClientBuffer)
+	}
+	s.close()
+	for range c.events {
+	}
+	if s.subscribe(NewValuesFilter("", "", "", "")) != nil {
+		t.Errorf("closed stream accept new client")
+	}
+	// unsubscribe after close doesn't close channel again
+	s.unsubscribe(c)
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// TftpBuildGroup object with TFTP build counters
+const TftpBuildGroup = "Cisco TFTP"
+
+// TftpBuildCounters counters changed by TFTP server during build of configuration files
+var TftpBuildCounters = []string{TftpBuildCount, TftpBuildDeviceCount, TftpBuildUnitCount, TftpBuildDialruleCount, TftpBuildSoftKeyCount, TftpBuildSignCount}
+
+// TftpBuild derive build in progress signal from changes of TFTP build counters between two collections
+type TftpBuild struct {
+	mutex      sync.Mutex
+	desc       *prometheus.Desc
+	previous   map[string]map[string]float64 // previous values by server and counter
+	current    map[string]map[string]float64 // current values from actual collection by server and counter
+	inProgress map[string]bool               // inProgress actual build state by server
+}
+
+// NewTftpBuild create TFTP build in progress collector
+func NewTftpBuild() *TftpBuild {
+	return &TftpBuild{
+		desc:       prometheus.NewDesc("cucm_tftp_build_in_progress", "TFTP node builds configuration files (build counters changed since previous collection)", []string{"server"}, nil),
+		previous:   make(map[string]map[string]float64),
+		current:    make(map[string]map[string]float64),
+		inProgress: make(map[string]bool),
+	}
+}
+
+// observe store collected value of build counter
+func (t *TftpBuild) observe(server string, group string, counter string, value float64) {
+	object, _ := splitInstance(group)
+	if object != TftpBuildGroup || !inSlice(counter, TftpBuildCounters) {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	values, ok := t.current[server]
+	if !ok {
+		values = make(map[string]float64)
+		t.current[server] = values
+	}
+	values[counter] = value
+}
+
+// update compare values from one collection with previous collection
+func (t *TftpBuild) update() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for server, values := range t.current {
+		previous, ok := t.previous[server]
+		changed := false
+		for counter, value := range values {
+			if old, exists := previous[counter]; ok && exists && old != value {
+				changed = true
+			}
+		}
+		if ok {
+			t.inProgress[server] = changed
+		}
+		t.previous[server] = values
+	}
+	t.current = make(map[string]map[string]float64)
+}
+
+// Describe implements prometheus.Collector
+func (t *TftpBuild) Describe(ch chan<- *prometheus.Desc) {
+	ch <- t.desc
+}
+
+// Collect implements prometheus.Collector
+func (t *TftpBuild) Collect(ch chan<- prometheus.Metric) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for server, inProgress := range t.inProgress {
+		value := float64(0)
+		if inProgress {
+			value = 1
+		}
+		ch <- prometheus.MustNewConstMetric(t.desc, prometheus.GaugeValue, value, server)
+	}
+}
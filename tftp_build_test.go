@@ -0,0 +1,55 @@
+package main
+
+import "testing"
+
+// tftpBuildValues collected build in progress values by server
+func tftpBuildValues(t *testing.T, b *TftpBuild) map[string]float64 {
+	t.Helper()
+	values := make(map[string]float64)
+	for _, m := range collectMetrics(t, b) {
+		values[labelValue(m, "server")] = metricValue(m)
+	}
+	return values
+}
+
+func TestTftpBuildInProgress(t *testing.T) {
+	b := NewTftpBuild()
+	collect := func(build float64, device float64) {
+		b.observe("tftp1", TftpBuildGroup, TftpBuildCount, build)
+		b.observe("tftp1", TftpBuildGroup, TftpBuildDeviceCount, device)
+		// counter out of build counters and other object are ignored
+		b.observe("tftp1", TftpBuildGroup, RequestsProcessed, build+device)
+		b.observe("tftp1", "Cisco CallManager", TftpBuildCount, build+1)
+		b.update()
+	}
+
+	collect(1, 100)
+	if got := tftpBuildValues(t, b); len(got) != 0 {
+		t.Errorf("first collection exports %v, want nothing without previous collection", got)
+	}
+	collect(1, 120)
+	if got := tftpBuildValues(t, b); got["tftp1"] != 1 {
+		t.Errorf("changed device count exports %v, want build in progress", got)
+	}
+	collect(1, 120)
+	if got := tftpBuildValues(t, b); got["tftp1"] != 0 {
+		t.Errorf("unchanged counters export %v, want build finished", got)
+	}
+	collect(2, 120)
+	if got := tftpBuildValues(t, b); got["tftp1"] != 1 {
+		t.Errorf("changed build count exports %v, want build in progress", got)
+	}
+}
+
+func TestTftpBuildNewCounter(t *testing.T) {
+	b := NewTftpBuild()
+	b.observe("tftp1", TftpBuildGroup, TftpBuildCount, 1)
+	b.update()
+	// counter missing in previous collection isn't change
+	b.observe("tftp1", TftpBuildGroup, TftpBuildCount, 1)
+	b.observe("tftp1", TftpBuildGroup, TftpBuildSignCount, 5)
+	b.update()
+	if got := tftpBuildValues(t, b); got["tftp1"] != 0 {
+		t.Errorf("new counter exports %v, want build not in progress", got)
+	}
+}